@@ -5,6 +5,8 @@ CURRENT_DIR=$(dirname "$0")
 rm -rf $CURRENT_DIR/.data
 mkdir -p $CURRENT_DIR/.data/cometbft
 mkdir -p $CURRENT_DIR/.data/sequencer
+mkdir -p $CURRENT_DIR/.data/rollup
+mkdir -p $CURRENT_DIR/.data/listeners
 
 # Reset the .data/cometbft/priv_validator_state.json file
 echo '{
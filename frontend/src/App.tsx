@@ -9,6 +9,12 @@ type EthData = {
   parentRoot: string;
   slot: number;
   propserIndex: number;
+  blockNumber?: number;
+  executionStateRoot?: string;
+  timestamp?: number;
+  baseFeePerGas?: string;
+  gasUsed?: number;
+  gasLimit?: number;
 };
 
 // bestest random ever
@@ -30,13 +36,21 @@ function App() {
     ws.current.onmessage = (event) => {
       console.log(`event.data is `);
       console.log(event.data);
-      const data = JSON.parse(event.data);
+      // the rollup sends accepted txs, the block data is their payload
+      const tx = JSON.parse(event.data);
+      const data = tx.payload ?? tx;
       const message: EthData = {
         blockHash: data.block_hash,
         stateRoot: data.state_root,
         parentRoot: data.parent_root,
         slot: data.slot,
         propserIndex: data.proposer_index,
+        blockNumber: data.block_number,
+        executionStateRoot: data.execution_state_root,
+        timestamp: data.timestamp,
+        baseFeePerGas: data.base_fee_per_gas,
+        gasUsed: data.gas_used,
+        gasLimit: data.gas_limit,
       };
       setEthDatas((prevMessages) => [...prevMessages, message]);
     };
@@ -63,6 +77,17 @@ function App() {
               <p>Parent Hash: {ethData.parentRoot}</p>{" "}
               <p>State Root: {ethData.stateRoot}</p> <p>Slot: {ethData.slot}</p>
               <p>Proposer Index: {ethData.propserIndex}</p>
+              {ethData.blockNumber !== undefined && (
+                <>
+                  <p>Block Number: {ethData.blockNumber}</p>
+                  <p>Execution State Root: {ethData.executionStateRoot}</p>
+                  <p>Timestamp: {ethData.timestamp}</p>
+                  <p>Base Fee: {ethData.baseFeePerGas}</p>
+                  <p>
+                    Gas Used: {ethData.gasUsed} / {ethData.gasLimit}
+                  </p>
+                </>
+              )}
             </section>
           ))}
           <div ref={endOfMessagesRef} />
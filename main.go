@@ -21,16 +21,21 @@ func main() {
 		log.Fatal(err)
 	}
 	log.Debugf("Read config from env: %+v\n", cfg)
-	cfg.SeqPrivate = "00fd4d6af5ac34d29d63a04ecf7da1ccfcbcdf7f7ed4042b8975e1c54e96d685"
 
-	ethBlockData := make(chan rollup.EthBlockData)
-	shutdownSignal := make(chan bool)
-	cl := rollup.NewChainListeners("https://beacon-nd-942-489-268.p2pify.com/c450ba1e6c5025d33dd14dc4c54f5cf6", ethBlockData, shutdownSignal)
+	listeners, err := rollup.LoadChainListeners(context.Background(), cfg.ChainListeners, cfg.ListenerDataDir)
+	if err != nil {
+		log.Fatal(err)
+	}
 
-	app := rollup.NewApp(cfg, ethBlockData)
+	reports := make(chan rollup.Report)
+	app := rollup.NewApp(cfg, reports)
 
 	fmt.Println("Running chain listeners in background!!")
-	go cl.Run()
+	for _, listener := range listeners {
+		if err := listener.Start(reports); err != nil {
+			log.Fatalf("failed to start %s listener: %s", listener.ChainID(), err)
+		}
+	}
 
 	fmt.Println("Running app!!")
 	app.Run()
@@ -40,6 +45,8 @@ func main() {
 	signal.Notify(c, os.Interrupt)
 	<-c
 
-	// tell all apps to shutdown gracefully
-	shutdownSignal <- true
+	// tell all listeners to shutdown gracefully
+	for _, listener := range listeners {
+		listener.Stop()
+	}
 }
@@ -0,0 +1,192 @@
+// Package merkle implements the merkle trees used by the oracle rollup, along with inclusion proofs that can be
+// verified without access to the rest of the tree.
+//
+// Tx roots are binary trees following RFC 6962: leaves are hashed as sha256(0x00 || leaf) and inner nodes as
+// sha256(0x01 || left || right), and a tree of n leaves is split at the largest power of two smaller than n.
+// State roots are sparse merkle trees, see SparseTree.
+package merkle
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+)
+
+var (
+	leafPrefix  = []byte{0x00}
+	innerPrefix = []byte{0x01}
+)
+
+// Hash is a sha256 hash which is hex encoded in JSON.
+type Hash [32]byte
+
+func (h Hash) String() string {
+	return hex.EncodeToString(h[:])
+}
+
+func (h Hash) MarshalText() ([]byte, error) {
+	return []byte(h.String()), nil
+}
+
+func (h *Hash) UnmarshalText(text []byte) error {
+	bs, err := hex.DecodeString(string(text))
+	if err != nil {
+		return err
+	}
+	if len(bs) != len(h) {
+		return fmt.Errorf("invalid hash length %d", len(bs))
+	}
+	copy(h[:], bs)
+	return nil
+}
+
+// EmptyRoot is the root of a tree without any leaves.
+func EmptyRoot() Hash {
+	return sha256.Sum256([]byte{})
+}
+
+// LeafHash returns the hash of a single leaf.
+func LeafHash(leaf []byte) Hash {
+	h := sha256.New()
+	h.Write(leafPrefix)
+	h.Write(leaf)
+	return Hash(h.Sum(nil))
+}
+
+func innerHash(left Hash, right Hash) Hash {
+	h := sha256.New()
+	h.Write(innerPrefix)
+	h.Write(left[:])
+	h.Write(right[:])
+	return Hash(h.Sum(nil))
+}
+
+// splitPoint returns the largest power of two smaller than n.
+func splitPoint(n int) int {
+	k := 1
+	for k*2 < n {
+		k *= 2
+	}
+	return k
+}
+
+// Root returns the root of the tree built over the given leaves.
+func Root(leaves [][]byte) Hash {
+	hashes := make([]Hash, len(leaves))
+	for i, leaf := range leaves {
+		hashes[i] = LeafHash(leaf)
+	}
+	return RootFromLeafHashes(hashes)
+}
+
+// RootFromLeafHashes returns the root of the tree built over already hashed leaves.
+func RootFromLeafHashes(hashes []Hash) Hash {
+	switch len(hashes) {
+	case 0:
+		return EmptyRoot()
+	case 1:
+		return hashes[0]
+	default:
+		k := splitPoint(len(hashes))
+		return innerHash(RootFromLeafHashes(hashes[:k]), RootFromLeafHashes(hashes[k:]))
+	}
+}
+
+// Proof proves that a leaf is included at Index in a tree of Total leaves.
+// Aunts are the sibling hashes on the path from the leaf to the root, starting next to the leaf.
+type Proof struct {
+	Index uint64 `json:"index"`
+	Total uint64 `json:"total"`
+	Aunts []Hash `json:"aunts"`
+}
+
+// NewProof builds an inclusion proof for the leaf at the given index.
+func NewProof(leaves [][]byte, index int) (*Proof, error) {
+	hashes := make([]Hash, len(leaves))
+	for i, leaf := range leaves {
+		hashes[i] = LeafHash(leaf)
+	}
+	return NewProofFromLeafHashes(hashes, index)
+}
+
+// NewProofFromLeafHashes builds an inclusion proof for the leaf at the given index from already hashed leaves.
+func NewProofFromLeafHashes(hashes []Hash, index int) (*Proof, error) {
+	if index < 0 || index >= len(hashes) {
+		return nil, fmt.Errorf("leaf index %d out of range for %d leaves", index, len(hashes))
+	}
+	return &Proof{
+		Index: uint64(index),
+		Total: uint64(len(hashes)),
+		Aunts: aunts(hashes, index),
+	}, nil
+}
+
+func aunts(hashes []Hash, index int) []Hash {
+	if len(hashes) <= 1 {
+		return []Hash{}
+	}
+	k := splitPoint(len(hashes))
+	if index < k {
+		return append(aunts(hashes[:k], index), RootFromLeafHashes(hashes[k:]))
+	}
+	return append(aunts(hashes[k:], index-k), RootFromLeafHashes(hashes[:k]))
+}
+
+// ComputeRoot returns the root implied by the proof for the given leaf hash.
+func (p *Proof) ComputeRoot(leafHash Hash) (Hash, error) {
+	if p.Total == 0 || p.Index >= p.Total {
+		return Hash{}, fmt.Errorf("leaf index %d out of range for %d leaves", p.Index, p.Total)
+	}
+	root, rest, err := computeRoot(leafHash, p.Index, p.Total, p.Aunts)
+	if err != nil {
+		return Hash{}, err
+	}
+	if len(rest) != 0 {
+		return Hash{}, errors.New("proof has too many aunts")
+	}
+	return root, nil
+}
+
+// computeRoot walks the tree top down and consumes aunts from the end of the list, which holds the aunts closest to
+// the root.
+func computeRoot(leafHash Hash, index uint64, total uint64, aunts []Hash) (Hash, []Hash, error) {
+	if total == 1 {
+		return leafHash, aunts, nil
+	}
+	if len(aunts) == 0 {
+		return Hash{}, nil, errors.New("proof has too few aunts")
+	}
+	sibling := aunts[len(aunts)-1]
+	aunts = aunts[:len(aunts)-1]
+	k := uint64(splitPoint(int(total)))
+	if index < k {
+		left, rest, err := computeRoot(leafHash, index, k, aunts)
+		if err != nil {
+			return Hash{}, nil, err
+		}
+		return innerHash(left, sibling), rest, nil
+	}
+	right, rest, err := computeRoot(leafHash, index-k, total-k, aunts)
+	if err != nil {
+		return Hash{}, nil, err
+	}
+	return innerHash(sibling, right), rest, nil
+}
+
+// Verify checks that the leaf is included in the tree with the given root.
+func (p *Proof) Verify(root Hash, leaf []byte) error {
+	return p.VerifyLeafHash(root, LeafHash(leaf))
+}
+
+// VerifyLeafHash checks that the already hashed leaf is included in the tree with the given root.
+func (p *Proof) VerifyLeafHash(root Hash, leafHash Hash) error {
+	computed, err := p.ComputeRoot(leafHash)
+	if err != nil {
+		return err
+	}
+	if computed != root {
+		return fmt.Errorf("computed root %s does not match expected root %s", computed, root)
+	}
+	return nil
+}
@@ -0,0 +1,120 @@
+package merkle
+
+import (
+	"encoding/hex"
+	"fmt"
+	"testing"
+)
+
+// rfc6962Leaves and rfc6962Roots are the test vectors of the certificate transparency reference implementation:
+// the roots of the trees over the first 1 to 8 leaves.
+var (
+	rfc6962Leaves = []string{"", "00", "10", "2021", "3031", "40414243", "5051525354555657", "606162636465666768696a6b6c6d6e6f"}
+	rfc6962Roots  = []string{
+		"6e340b9cffb37a989ca544e6bb780a2c78901d3fb33738768511a30617afa01d",
+		"fac54203e7cc696cf0dfcb42c92a1d9dbaf70ad9e621f4bd8d98662f00e3c125",
+		"aeb6bcfe274b70a14fb067a5e5578264db0fa9b51af5e0ba159158f329e06e77",
+		"d37ee418976dd95753c1c73862b9398fa2a2cf9b4ff0fdfe8b30cd95209614b7",
+		"4e3bbb1f7b478dcfe71fb631631519a3bca12c9aefca1612bfce4c13a86264d4",
+		"76e67dadbcdf1e10e1b74ddc608abd2f98dfb16fbce75277b5232a127f2087ef",
+		"ddb89be403809e325750d3d263cd78929c2942b7942a34b77e122c9594a74c8c",
+		"5dc9da79a70659a9ad559cb701ded9a2ab9d823aad2f4960cfe370eff4604328",
+	}
+)
+
+func TestRootMatchesRFC6962Vectors(t *testing.T) {
+	leaves := [][]byte{}
+	for i, leafHex := range rfc6962Leaves {
+		leaf, err := hex.DecodeString(leafHex)
+		if err != nil {
+			t.Fatal(err)
+		}
+		leaves = append(leaves, leaf)
+		if root := Root(leaves); root.String() != rfc6962Roots[i] {
+			t.Errorf("root of %d leaves is %s, expected %s", i+1, root, rfc6962Roots[i])
+		}
+	}
+	if Root(nil) != EmptyRoot() || EmptyRoot().String() != "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855" {
+		t.Errorf("empty root is %s", Root(nil))
+	}
+}
+
+func testLeaves(n int) [][]byte {
+	leaves := make([][]byte, n)
+	for i := range leaves {
+		leaves[i] = []byte(fmt.Sprintf("leaf/%d", i))
+	}
+	return leaves
+}
+
+func TestProofVerifiesEveryIndex(t *testing.T) {
+	for n := 1; n <= 33; n++ {
+		leaves := testLeaves(n)
+		root := Root(leaves)
+		for i := range leaves {
+			proof, err := NewProof(leaves, i)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if proof.Index != uint64(i) || proof.Total != uint64(n) {
+				t.Fatalf("%d leaves, index %d: proof has index %d of %d", n, i, proof.Index, proof.Total)
+			}
+			if err := proof.Verify(root, leaves[i]); err != nil {
+				t.Fatalf("%d leaves, index %d: %v", n, i, err)
+			}
+		}
+		if _, err := NewProof(leaves, n); err == nil {
+			t.Fatalf("%d leaves: built a proof for an index out of range", n)
+		}
+		if _, err := NewProof(leaves, -1); err == nil {
+			t.Fatalf("%d leaves: built a proof for a negative index", n)
+		}
+	}
+}
+
+func TestProofRejectsTampering(t *testing.T) {
+	for _, n := range []int{2, 3, 5, 8, 13} {
+		leaves := testLeaves(n)
+		root := Root(leaves)
+		for i := range leaves {
+			proof, err := NewProof(leaves, i)
+			if err != nil {
+				t.Fatal(err)
+			}
+			tampered := map[string]struct {
+				proof Proof
+				leaf  []byte
+			}{
+				"wrong index":      {Proof{Index: uint64((i + 1) % n), Total: proof.Total, Aunts: proof.Aunts}, leaves[i]},
+				"index past total": {Proof{Index: proof.Total, Total: proof.Total, Aunts: proof.Aunts}, leaves[i]},
+				"wrong leaf":       {*proof, leaves[(i+1)%n]},
+				"extra aunt":       {Proof{Index: proof.Index, Total: proof.Total, Aunts: append([]Hash{{1}}, proof.Aunts...)}, leaves[i]},
+				"missing aunt":     {Proof{Index: proof.Index, Total: proof.Total, Aunts: proof.Aunts[1:]}, leaves[i]},
+				"wrong aunt":       {Proof{Index: proof.Index, Total: proof.Total, Aunts: append([]Hash{{1}}, proof.Aunts[1:]...)}, leaves[i]},
+			}
+			for name, c := range tampered {
+				if err := c.proof.Verify(root, c.leaf); err == nil {
+					t.Errorf("%d leaves, index %d: %s verified", n, i, name)
+				}
+			}
+			// the leaf hash is checked rather than the leaf, a leaf which equals its hash doesn't verify
+			leafHash := LeafHash(leaves[i])
+			if err := proof.Verify(root, leafHash[:]); err == nil {
+				t.Errorf("%d leaves, index %d: the leaf hash verified as a leaf", n, i)
+			}
+		}
+	}
+
+	single := testLeaves(1)
+	proof, err := NewProof(single, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	proof.Aunts = []Hash{{1}}
+	if err := proof.Verify(Root(single), single[0]); err == nil {
+		t.Error("a proof of a single leaf with an aunt verified")
+	}
+	if err := (&Proof{}).Verify(EmptyRoot(), nil); err == nil {
+		t.Error("a proof of an empty tree verified")
+	}
+}
@@ -0,0 +1,225 @@
+package merkle
+
+import (
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// sparseDepth is the depth of a sparse tree, the number of bits of a key path.
+const sparseDepth = 256
+
+// SparseTree is an immutable sparse merkle tree of key/value pairs, which is used for the rollup state.
+//
+// A key is stored at the leaf of the path given by the bits of sha256(key), starting with the most significant bit.
+// Leaves are hashed as sha256(0x00 || sha256(key) || sha256(value)) and inner nodes as sha256(0x01 || left || right),
+// where an empty subtree hashes to the zero hash. A subtree with a single leaf is replaced by the leaf, so the tree
+// of n keys has a depth of about log2(n) and an update only rehashes the nodes on the path of its key.
+//
+// Updates return a new tree which shares all unchanged nodes with the original, so trees can be kept and read
+// concurrently while newer versions are built from them.
+type SparseTree struct {
+	root *sparseNode
+}
+
+type sparseNode struct {
+	hash Hash
+	// leaf nodes have a path, inner nodes have at least two leaves below them
+	leaf        bool
+	path        Hash
+	left, right *sparseNode
+}
+
+// SparseEntry is a key/value pair of a sparse tree.
+type SparseEntry struct {
+	Key   []byte
+	Value []byte
+}
+
+// NewSparseTree builds a tree of the given entries, whose keys have to be distinct. It is faster than setting the
+// entries one by one, since every node is hashed once.
+func NewSparseTree(entries []SparseEntry) *SparseTree {
+	leaves := make([]*sparseNode, len(entries))
+	for i, entry := range entries {
+		leaves[i] = newSparseLeaf(sha256.Sum256(entry.Key), entry.Value)
+	}
+	sort.Slice(leaves, func(i, j int) bool {
+		return string(leaves[i].path[:]) < string(leaves[j].path[:])
+	})
+	return &SparseTree{root: buildSparse(leaves, 0)}
+}
+
+// buildSparse builds the subtree at the given depth of leaves sorted by path.
+func buildSparse(leaves []*sparseNode, depth int) *sparseNode {
+	switch len(leaves) {
+	case 0:
+		return nil
+	case 1:
+		return leaves[0]
+	}
+	split := sort.Search(len(leaves), func(i int) bool { return pathBit(leaves[i].path, depth) == 1 })
+	return newSparseInner(buildSparse(leaves[:split], depth+1), buildSparse(leaves[split:], depth+1))
+}
+
+func newSparseLeaf(path Hash, value []byte) *sparseNode {
+	valueHash := sha256.Sum256(value)
+	h := sha256.New()
+	h.Write(leafPrefix)
+	h.Write(path[:])
+	h.Write(valueHash[:])
+	return &sparseNode{hash: Hash(h.Sum(nil)), leaf: true, path: path}
+}
+
+func newSparseInner(left *sparseNode, right *sparseNode) *sparseNode {
+	return &sparseNode{hash: innerHash(left.nodeHash(), right.nodeHash()), left: left, right: right}
+}
+
+// nodeHash returns the hash of the node, which is the zero hash for an empty subtree.
+func (n *sparseNode) nodeHash() Hash {
+	if n == nil {
+		return Hash{}
+	}
+	return n.hash
+}
+
+// pathBit returns the bit of the path at the given depth.
+func pathBit(path Hash, depth int) byte {
+	return (path[depth/8] >> (7 - depth%8)) & 1
+}
+
+// Root returns the root of the tree, which is the zero hash for an empty tree.
+func (t *SparseTree) Root() Hash {
+	if t == nil {
+		return Hash{}
+	}
+	return t.root.nodeHash()
+}
+
+// Set returns a tree in which the key has the given value.
+func (t *SparseTree) Set(key []byte, value []byte) *SparseTree {
+	leaf := newSparseLeaf(sha256.Sum256(key), value)
+	if t == nil {
+		return &SparseTree{root: leaf}
+	}
+	return &SparseTree{root: insertSparse(t.root, leaf, 0)}
+}
+
+func insertSparse(n *sparseNode, leaf *sparseNode, depth int) *sparseNode {
+	switch {
+	case n == nil:
+		return leaf
+	case n.leaf && n.path == leaf.path:
+		return leaf
+	case n.leaf:
+		return joinSparse(n, leaf, depth)
+	case pathBit(leaf.path, depth) == 0:
+		return newSparseInner(insertSparse(n.left, leaf, depth+1), n.right)
+	default:
+		return newSparseInner(n.left, insertSparse(n.right, leaf, depth+1))
+	}
+}
+
+// joinSparse returns the subtree at the given depth of two leaves with different paths.
+func joinSparse(a *sparseNode, b *sparseNode, depth int) *sparseNode {
+	aBit, bBit := pathBit(a.path, depth), pathBit(b.path, depth)
+	switch {
+	case aBit == bBit && aBit == 0:
+		return newSparseInner(joinSparse(a, b, depth+1), nil)
+	case aBit == bBit:
+		return newSparseInner(nil, joinSparse(a, b, depth+1))
+	case aBit == 0:
+		return newSparseInner(a, b)
+	default:
+		return newSparseInner(b, a)
+	}
+}
+
+// Delete returns a tree without the key.
+func (t *SparseTree) Delete(key []byte) *SparseTree {
+	if t == nil {
+		return nil
+	}
+	root := deleteSparse(t.root, sha256.Sum256(key), 0)
+	if root == t.root {
+		return t
+	}
+	return &SparseTree{root: root}
+}
+
+func deleteSparse(n *sparseNode, path Hash, depth int) *sparseNode {
+	switch {
+	case n == nil:
+		return nil
+	case n.leaf && n.path == path:
+		return nil
+	case n.leaf:
+		return n
+	}
+	left, right := n.left, n.right
+	if pathBit(path, depth) == 0 {
+		left = deleteSparse(left, path, depth+1)
+	} else {
+		right = deleteSparse(right, path, depth+1)
+	}
+	switch {
+	case left == n.left && right == n.right:
+		return n
+	// a subtree with a single leaf is replaced by the leaf
+	case left == nil && (right == nil || right.leaf):
+		return right
+	case right == nil && left.leaf:
+		return left
+	default:
+		return newSparseInner(left, right)
+	}
+}
+
+// SparseProof proves that a key has a value in a sparse tree. Siblings are the hashes of the siblings of the nodes
+// on the path from the root to the key's leaf, starting below the root. Empty siblings are the zero hash.
+type SparseProof struct {
+	Siblings []Hash `json:"siblings"`
+}
+
+// Prove returns an inclusion proof for the key, which has to be in the tree.
+func (t *SparseTree) Prove(key []byte) (*SparseProof, error) {
+	path := Hash(sha256.Sum256(key))
+	siblings := []Hash{}
+	var n *sparseNode
+	if t != nil {
+		n = t.root
+	}
+	for depth := 0; n != nil && !n.leaf; depth++ {
+		if pathBit(path, depth) == 0 {
+			siblings = append(siblings, n.right.nodeHash())
+			n = n.left
+		} else {
+			siblings = append(siblings, n.left.nodeHash())
+			n = n.right
+		}
+	}
+	if n == nil || n.path != path {
+		return nil, fmt.Errorf("key %q not found in tree", key)
+	}
+	return &SparseProof{Siblings: siblings}, nil
+}
+
+// VerifyKV checks that the key has the value in the sparse tree with the given root.
+func (p *SparseProof) VerifyKV(root Hash, key []byte, value []byte) error {
+	if len(p.Siblings) > sparseDepth {
+		return errors.New("proof has too many siblings")
+	}
+	leaf := newSparseLeaf(sha256.Sum256(key), value)
+	computed := leaf.hash
+	for depth := len(p.Siblings) - 1; depth >= 0; depth-- {
+		if pathBit(leaf.path, depth) == 0 {
+			computed = innerHash(computed, p.Siblings[depth])
+		} else {
+			computed = innerHash(p.Siblings[depth], computed)
+		}
+	}
+	if computed != root {
+		return fmt.Errorf("computed root %s does not match expected root %s", computed, root)
+	}
+	return nil
+}
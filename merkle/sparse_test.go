@@ -0,0 +1,86 @@
+package merkle
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+)
+
+func TestSparseTreeUpdatesMatchRebuild(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	values := map[string][]byte{}
+	tree := &SparseTree{}
+	for i := 0; i < 2000; i++ {
+		key := fmt.Sprintf("key/%d", rng.Intn(300))
+		if rng.Intn(4) == 0 {
+			delete(values, key)
+			tree = tree.Delete([]byte(key))
+		} else {
+			value := []byte(fmt.Sprintf("value/%d", i))
+			values[key] = value
+			tree = tree.Set([]byte(key), value)
+		}
+		if i%100 != 0 {
+			continue
+		}
+		entries := []SparseEntry{}
+		for k, v := range values {
+			entries = append(entries, SparseEntry{Key: []byte(k), Value: v})
+		}
+		if rebuilt := NewSparseTree(entries); rebuilt.Root() != tree.Root() {
+			t.Fatalf("step %d: root %s does not match rebuilt root %s", i, tree.Root(), rebuilt.Root())
+		}
+	}
+
+	for key, value := range values {
+		proof, err := tree.Prove([]byte(key))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := proof.VerifyKV(tree.Root(), []byte(key), value); err != nil {
+			t.Fatalf("%s: %v", key, err)
+		}
+		if err := proof.VerifyKV(tree.Root(), []byte(key), []byte("other")); err == nil {
+			t.Fatalf("%s: proof verified with a wrong value", key)
+		}
+	}
+}
+
+func TestSparseTreeIsImmutable(t *testing.T) {
+	tree := NewSparseTree([]SparseEntry{{Key: []byte("a"), Value: []byte("1")}, {Key: []byte("b"), Value: []byte("2")}})
+	root := tree.Root()
+	tree.Set([]byte("c"), []byte("3"))
+	tree.Delete([]byte("a"))
+	if tree.Root() != root {
+		t.Fatal("updates modified the original tree")
+	}
+}
+
+func TestSparseTreeDeleteAll(t *testing.T) {
+	tree := &SparseTree{}
+	for i := 0; i < 10; i++ {
+		tree = tree.Set([]byte{byte(i)}, []byte{byte(i)})
+	}
+	for i := 0; i < 10; i++ {
+		tree = tree.Delete([]byte{byte(i)})
+	}
+	if tree.Root() != (Hash{}) {
+		t.Fatalf("empty tree has root %s", tree.Root())
+	}
+	if _, err := tree.Prove([]byte{0}); err == nil {
+		t.Fatal("proved a missing key")
+	}
+}
+
+func BenchmarkSparseTreeSet(b *testing.B) {
+	entries := make([]SparseEntry, 100000)
+	for i := range entries {
+		entries[i] = SparseEntry{Key: []byte(fmt.Sprintf("key/%d", i)), Value: []byte("value")}
+	}
+	tree := NewSparseTree(entries)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		tree = tree.Set([]byte(fmt.Sprintf("key/%d", i%len(entries))), []byte(fmt.Sprint(i)))
+		tree.Root()
+	}
+}
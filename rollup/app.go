@@ -1,14 +1,18 @@
 package rollup
 
 import (
+	"blockchain-oracle/merkle"
 	"crypto/ed25519"
-	"crypto/sha256"
+	"crypto/subtle"
 	"encoding/hex"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net"
 	"net/http"
+	"slices"
 	"strconv"
+	"strings"
 	"sync"
 
 	"github.com/gorilla/websocket"
@@ -30,60 +34,147 @@ var wsUpgrader = websocket.Upgrader{
 
 // App is the main application struct, containing all the necessary components.
 type App struct {
-	executionRPC     string
-	sequencerRPC     string
-	sequencerClient  SequencerClient
-	restRouter       *mux.Router
-	restAddr         string
-	rollup           *Rollup
-	rollupName       string
-	rollupID         []byte
-	ethBlockDataRcvr chan EthBlockData
-	wsClients        WSClientList
-	newBlockChan     chan Block
-	lock             sync.RWMutex
-}
-
-func NewApp(cfg Config, ethBlockDataRcvr chan EthBlockData) *App {
+	executionRPC    string
+	sequencerRPC    string
+	sequencerClient SequencerClient
+	restRouter      *mux.Router
+	restAddr        string
+	rollup          *Rollup
+	rollupName      string
+	rollupID        []byte
+	genesis         *Genesis
+	reporterKey     ed25519.PrivateKey
+	reports         chan Report
+	wsClients       WSClientList
+	newBlockChan    chan Block
+	lock            sync.RWMutex
+
+	// submissions are the txs posted to the REST API. They are sequenced by the report loop, which is the only user
+	// of the sequencer client.
+	submissions chan txSubmission
+	// txAPIToken is the bearer token required to post txs. The endpoint is disabled if it is empty.
+	txAPIToken string
+}
+
+func NewApp(cfg Config, reports chan Report) *App {
 	log.Debugf("Creating new rollup app with config: %v", cfg)
 
 	newBlockChan := make(chan Block, 20)
 
-	rollup := NewRollup(newBlockChan)
+	var store BlockStore
+	if cfg.DataDir == "" {
+		log.Warn("no data dir configured, blocks will only be kept in memory")
+		store = NewMemoryStore()
+	} else {
+		pebbleStore, err := NewPebbleStore(cfg.DataDir)
+		if err != nil {
+			panic(err)
+		}
+		store = pebbleStore
+	}
+
+	var genesis *Genesis
+	if cfg.GenesisFile == "" {
+		log.Warn("no genesis file configured, using default genesis")
+		genesis = DefaultGenesis(cfg.RollupName)
+	} else {
+		loaded, err := LoadGenesis(cfg.GenesisFile)
+		if err != nil {
+			panic(err)
+		}
+		genesis = loaded
+	}
+	if genesis.RollupName != cfg.RollupName {
+		log.Warnf("rollup name %s from genesis overrides configured rollup name %s", genesis.RollupName, cfg.RollupName)
+	}
+
+	rollup, err := NewRollup(store, genesis, newBlockChan)
+	if err != nil {
+		panic(err)
+	}
 	router := mux.NewRouter()
 
-	rollupID := sha256.Sum256([]byte(cfg.RollupName))
+	rollupID := genesis.RollupID()
 
 	// sequencer private key
+	if cfg.SeqPrivate == "" {
+		panic(errors.New("SEQUENCER_PRIVATE is required"))
+	}
 	privateKeyBytes, err := hex.DecodeString(cfg.SeqPrivate)
 	if err != nil {
 		panic(err)
 	}
 	private := ed25519.NewKeyFromSeed(privateKeyBytes)
+	if err := checkSequencerKey(rollup, private.Public().(ed25519.PublicKey)); err != nil {
+		panic(err)
+	}
+
+	// reporter private key
+	reporterKey := private
+	if cfg.ReporterPrivate != "" {
+		reporterKeyBytes, err := hex.DecodeString(cfg.ReporterPrivate)
+		if err != nil {
+			panic(err)
+		}
+		reporterKey = ed25519.NewKeyFromSeed(reporterKeyBytes)
+	}
+	log.Infof("signing reports as reporter %x", reporterKey.Public())
 
 	return &App{
-		executionRPC:     cfg.ConductorRpc,
-		sequencerRPC:     cfg.SequencerRpc,
-		sequencerClient:  *NewSequencerClient("http://cometbft:26657", rollupID[:], private),
-		restRouter:       router,
-		restAddr:         cfg.RESTApiPort,
-		rollup:           &rollup,
-		rollupName:       cfg.RollupName,
-		rollupID:         rollupID[:],
-		ethBlockDataRcvr: ethBlockDataRcvr,
-		newBlockChan:     newBlockChan,
-		wsClients:        map[*WSClient]bool{},
+		executionRPC:    cfg.ConductorRpc,
+		sequencerRPC:    cfg.SequencerRpc,
+		sequencerClient: *NewSequencerClient("http://cometbft:26657", rollupID, private),
+		restRouter:      router,
+		restAddr:        cfg.RESTApiPort,
+		rollup:          rollup,
+		rollupName:      genesis.RollupName,
+		rollupID:        rollupID,
+		genesis:         genesis,
+		reporterKey:     reporterKey,
+		reports:         reports,
+		submissions:     make(chan txSubmission),
+		txAPIToken:      cfg.TxAPIToken,
+		newBlockChan:    newBlockChan,
+		wsClients:       map[*WSClient]bool{},
+	}
+}
+
+// checkSequencerKey refuses a sequencer key which is an admin in the latest state. The sequencer key is stored on
+// the node, so governance would be controlled by anyone who gets hold of the node's config.
+func checkSequencerKey(rollup *Rollup, publicKey ed25519.PublicKey) error {
+	snapshot := rollup.Snapshot()
+	state, err := snapshot.GetState(snapshot.GetLatestBlock().Height)
+	if err != nil {
+		return err
+	}
+	admins, err := GetAdminSet(state)
+	if err != nil {
+		return err
+	}
+	if admins != nil && slices.Contains(admins.Admins, hex.EncodeToString(publicKey)) {
+		return fmt.Errorf("sequencer key %x is an admin, admin keys must not be stored on the node", publicKey)
 	}
+	return nil
 }
 
 // makeExecutionServer creates a new ExecutionServiceServer.
 func (a *App) makeExecutionServer() *ExecutionServiceServerV1Alpha2 {
-	return NewExecutionServiceServerV1Alpha2(a.rollup, a.rollupID)
+	return NewExecutionServiceServerV1Alpha2(a.rollup, a.genesis)
 }
 
 // setupRestRoutes sets up the routes for the REST API.
 func (a *App) setupRestRoutes() {
 	a.restRouter.HandleFunc("/block/{height}", a.getBlock).Methods("GET")
+	a.restRouter.HandleFunc("/block/{height}/receipts", a.getReceipts).Methods("GET")
+	a.restRouter.HandleFunc("/block/{height}/tx/{index}/proof", a.getTxProof).Methods("GET")
+	a.restRouter.HandleFunc("/chain/{chain}", a.getChainRecord).Methods("GET")
+	a.restRouter.HandleFunc("/chain/{chain}/pending", a.getPendingReports).Methods("GET")
+	a.restRouter.HandleFunc("/proof/{chain}", a.getChainRecordProof).Methods("GET")
+	a.restRouter.HandleFunc("/governance/audit", a.getGovernanceAudit).Methods("GET")
+	a.restRouter.HandleFunc("/evidence", a.getEvidence).Methods("GET")
+	if a.txAPIToken != "" {
+		a.restRouter.HandleFunc("/tx", a.postTransaction).Methods("POST")
+	}
 	a.restRouter.HandleFunc("/ws", a.serveWS)
 }
 
@@ -125,6 +216,19 @@ func (a *App) removeWSClient(client *WSClient) {
 	}
 }
 
+// broadcastWS sends the message to all connected ws clients without blocking on slow clients.
+func (a *App) broadcastWS(message []byte) {
+	a.lock.RLock()
+	defer a.lock.RUnlock()
+	for client := range a.wsClients {
+		select {
+		case client.egress <- message:
+		default:
+			log.Warnf("Could not send message to ws client: %s", message)
+		}
+	}
+}
+
 func (a *App) getBlock(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	heightStr, ok := vars["height"]
@@ -141,7 +245,11 @@ func (a *App) getBlock(w http.ResponseWriter, r *http.Request) {
 	}
 
 	log.Debugf("getting block %d\n", height)
-	block, err := a.rollup.GetSingleBlock(uint32(height))
+	block, err := a.rollup.Snapshot().GetBlock(uint32(height))
+	if errors.Is(err, ErrBlockNotFound) {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
 	if err != nil {
 		log.Errorf("error getting block: %s\n", err)
 		w.WriteHeader(http.StatusInternalServerError)
@@ -158,7 +266,370 @@ func (a *App) getBlock(w http.ResponseWriter, r *http.Request) {
 	w.Write(blockJson)
 }
 
-func (a *App) postEthBlockData(w http.ResponseWriter, r *http.Request) {
+func (a *App) getReceipts(w http.ResponseWriter, r *http.Request) {
+	height, err := strconv.Atoi(mux.Vars(r)["height"])
+	if err != nil {
+		log.Errorf("error converting height to int: %s\n", err)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	log.Debugf("getting receipts of block %d\n", height)
+	block, err := a.rollup.Snapshot().GetBlock(uint32(height))
+	if errors.Is(err, ErrBlockNotFound) {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		log.Errorf("error getting block: %s\n", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	receiptsJson, err := json.Marshal(block.Receipts)
+	if err != nil {
+		log.Errorf("error marshalling receipts: %s\n", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.Write(receiptsJson)
+}
+
+// TxProofResponse proves that Tx is included at Proof.Index in the block at Height.
+type TxProofResponse struct {
+	Height  uint32        `json:"height"`
+	TxRoot  merkle.Hash   `json:"tx_root"`
+	Tx      *Transaction  `json:"tx"`
+	TxBytes string        `json:"tx_bytes"`
+	Proof   *merkle.Proof `json:"proof"`
+}
+
+func (a *App) getTxProof(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	height, err := strconv.Atoi(vars["height"])
+	if err != nil {
+		log.Errorf("error converting height to int: %s\n", err)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	index, err := strconv.Atoi(vars["index"])
+	if err != nil {
+		log.Errorf("error converting tx index to int: %s\n", err)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	log.Debugf("getting proof for tx %d in block %d\n", index, height)
+	block, err := a.rollup.Snapshot().GetBlock(uint32(height))
+	if errors.Is(err, ErrBlockNotFound) {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		log.Errorf("error getting block: %s\n", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if index < 0 || index >= len(block.Txs) {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	proof, err := block.TxProof(index)
+	if err != nil {
+		log.Errorf("error building tx proof: %s\n", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	// txs which fail to decode are still part of the block, so the proof is returned without the decoded tx
+	tx, err := DecodeTransaction(block.Txs[index])
+	if err != nil {
+		tx = nil
+	}
+
+	proofJson, err := json.Marshal(TxProofResponse{
+		Height:  block.Height,
+		TxRoot:  block.TxRoot,
+		Tx:      tx,
+		TxBytes: hex.EncodeToString(block.Txs[index]),
+		Proof:   proof,
+	})
+	if err != nil {
+		log.Errorf("error marshalling tx proof: %s\n", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.Write(proofJson)
+}
+
+// ChainRecordResponse is the latest record of a chain in the state after the block at Height. The finality level
+// is selected with the finality query parameter, which defaults to the head.
+type ChainRecordResponse struct {
+	Height    uint32       `json:"height"`
+	StateRoot merkle.Hash  `json:"state_root"`
+	Record    *ChainRecord `json:"record"`
+}
+
+// ChainRecordProofResponse is the latest record of a chain in the state after the firm block at Height,
+// along with a proof which can be checked with VerifyChainRecordProof.
+type ChainRecordProofResponse struct {
+	Height     uint32       `json:"height"`
+	Record     *ChainRecord `json:"record"`
+	StateProof *StateProof  `json:"state_proof"`
+}
+
+func (a *App) getChainRecord(w http.ResponseWriter, r *http.Request) {
+	chainID := mux.Vars(r)["chain"]
+	finality, err := ParseFinality(r.URL.Query().Get("finality"))
+	if err != nil {
+		log.Errorf("error parsing finality: %s\n", err)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	log.Debugf("getting latest %s record of chain %s\n", finality, chainID)
+	snapshot := a.rollup.Snapshot()
+	block := snapshot.GetLatestBlock()
+	state, err := snapshot.GetState(block.Height)
+	if err != nil {
+		log.Errorf("error getting state: %s\n", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	record, err := GetChainRecord(state, chainID, finality)
+	if err != nil {
+		log.Errorf("error getting chain record: %s\n", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if record == nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	recordJson, err := json.Marshal(ChainRecordResponse{
+		Height:    block.Height,
+		StateRoot: block.StateRoot,
+		Record:    record,
+	})
+	if err != nil {
+		log.Errorf("error marshalling chain record: %s\n", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.Write(recordJson)
+}
+
+// PendingReportsResponse is the tallies of the reports of a finality level of a chain which haven't reached quorum
+// in the state after the block at Height.
+type PendingReportsResponse struct {
+	Height    uint32          `json:"height"`
+	Threshold uint32          `json:"threshold"`
+	Pending   []PendingReport `json:"pending"`
+}
+
+func (a *App) getPendingReports(w http.ResponseWriter, r *http.Request) {
+	chainID := mux.Vars(r)["chain"]
+	finality, err := ParseFinality(r.URL.Query().Get("finality"))
+	if err != nil {
+		log.Errorf("error parsing finality: %s\n", err)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	log.Debugf("getting pending %s reports of chain %s\n", finality, chainID)
+	snapshot := a.rollup.Snapshot()
+	block := snapshot.GetLatestBlock()
+	state, err := snapshot.GetState(block.Height)
+	if err != nil {
+		log.Errorf("error getting state: %s\n", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	threshold, err := GetChainQuorumThreshold(state, chainID)
+	if err != nil {
+		log.Errorf("error getting quorum threshold: %s\n", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	pending, err := GetPendingReports(state, chainID, finality)
+	if err != nil {
+		log.Errorf("error getting pending reports: %s\n", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	pendingJson, err := json.Marshal(PendingReportsResponse{
+		Height:    block.Height,
+		Threshold: threshold,
+		Pending:   pending,
+	})
+	if err != nil {
+		log.Errorf("error marshalling pending reports: %s\n", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.Write(pendingJson)
+}
+
+// getChainRecordProof returns a proof of the latest record of a finality level of a chain against the firm block.
+func (a *App) getChainRecordProof(w http.ResponseWriter, r *http.Request) {
+	chainID := mux.Vars(r)["chain"]
+	finality, err := ParseFinality(r.URL.Query().Get("finality"))
+	if err != nil {
+		log.Errorf("error parsing finality: %s\n", err)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	log.Debugf("getting proof of latest %s record of chain %s\n", finality, chainID)
+	snapshot := a.rollup.Snapshot()
+	block, err := snapshot.GetFirmBlock()
+	if err != nil {
+		log.Errorf("error getting firm block: %s\n", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	state, err := snapshot.GetState(block.Height)
+	if err != nil {
+		log.Errorf("error getting state: %s\n", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if _, ok := state.Get(LatestRecordKey(chainID, finality)); !ok {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	proof, err := NewStateProof(block, state, LatestRecordKey(chainID, finality))
+	if err != nil {
+		log.Errorf("error building state proof: %s\n", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	record, err := GetChainRecord(state, chainID, finality)
+	if err != nil {
+		log.Errorf("error getting chain record: %s\n", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	proofJson, err := json.Marshal(ChainRecordProofResponse{
+		Height:     block.Height,
+		Record:     record,
+		StateProof: proof,
+	})
+	if err != nil {
+		log.Errorf("error marshalling state proof: %s\n", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.Write(proofJson)
+}
+
+// GovernanceAuditResponse is the admin set and the executed governance txs in the state after the block at Height.
+type GovernanceAuditResponse struct {
+	Height  uint32             `json:"height"`
+	Admins  *AdminSet          `json:"admins"`
+	Records []GovernanceRecord `json:"records"`
+}
+
+func (a *App) getGovernanceAudit(w http.ResponseWriter, r *http.Request) {
+	log.Debug("getting governance audit trail\n")
+	snapshot := a.rollup.Snapshot()
+	block := snapshot.GetLatestBlock()
+	state, err := snapshot.GetState(block.Height)
+	if err != nil {
+		log.Errorf("error getting state: %s\n", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	admins, err := GetAdminSet(state)
+	if err != nil {
+		log.Errorf("error getting admin set: %s\n", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	records, err := GetGovernanceRecords(state)
+	if err != nil {
+		log.Errorf("error getting governance records: %s\n", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	auditJson, err := json.Marshal(GovernanceAuditResponse{
+		Height:  block.Height,
+		Admins:  admins,
+		Records: records,
+	})
+	if err != nil {
+		log.Errorf("error marshalling governance audit trail: %s\n", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.Write(auditJson)
+}
+
+// EvidenceResponse is the equivocation evidence in the state after the block at Height.
+type EvidenceResponse struct {
+	Height   uint32     `json:"height"`
+	Evidence []Evidence `json:"evidence"`
+}
+
+func (a *App) getEvidence(w http.ResponseWriter, r *http.Request) {
+	log.Debug("getting equivocation evidence\n")
+	snapshot := a.rollup.Snapshot()
+	block := snapshot.GetLatestBlock()
+	state, err := snapshot.GetState(block.Height)
+	if err != nil {
+		log.Errorf("error getting state: %s\n", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	evidence, err := GetEvidence(state)
+	if err != nil {
+		log.Errorf("error getting evidence: %s\n", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	evidenceJson, err := json.Marshal(EvidenceResponse{
+		Height:   block.Height,
+		Evidence: evidence,
+	})
+	if err != nil {
+		log.Errorf("error marshalling evidence: %s\n", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.Write(evidenceJson)
+}
+
+// txSubmission is a tx posted to the REST API along with the channel its sequencing result is sent on.
+type txSubmission struct {
+	tx     Transaction
+	result chan error
+}
+
+// authorized checks the bearer token of a request against the tx api token in constant time.
+func (a *App) authorized(r *http.Request) bool {
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	return ok && subtle.ConstantTimeCompare([]byte(token), []byte(a.txAPIToken)) == 1
+}
+
+// postTransaction sequences a governance tx signed by the admins. It requires the tx api token, since sequencing
+// spends the node's sequencer funds, and only accepts txs with enough admin signatures for the latest admin set.
+func (a *App) postTransaction(w http.ResponseWriter, r *http.Request) {
+	if !a.authorized(r) {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
 	var tx Transaction
 	err := json.NewDecoder(r.Body).Decode(&tx)
 	if err != nil {
@@ -166,13 +637,71 @@ func (a *App) postEthBlockData(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusBadRequest)
 		return
 	}
-	resp, err := a.sequencerClient.SequenceTx(tx)
-	if err != nil {
-		log.Errorf("error sending message: %s\n", err)
-		w.WriteHeader(http.StatusInternalServerError)
+	if err := a.checkSubmission(&tx); err != nil {
+		log.Errorf("rejecting transaction: %s\n", err)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	submission := txSubmission{tx: tx, result: make(chan error, 1)}
+	select {
+	case a.submissions <- submission:
+	case <-r.Context().Done():
 		return
 	}
-	log.WithField("responseCode", resp.Code).Debug("transaction submission result")
+	select {
+	case err := <-submission.result:
+		if err != nil {
+			log.Errorf("error sending message: %s\n", err)
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	case <-r.Context().Done():
+	}
+}
+
+// checkSubmission checks that a posted tx is a governance tx with the signatures of enough admins of the latest
+// admin set, so that txs which will be rejected aren't sequenced.
+func (a *App) checkSubmission(tx *Transaction) error {
+	if err := tx.validate(); err != nil {
+		return err
+	}
+	if tx.PayloadType != PayloadTypeGovernance || tx.ChainID != GovernanceChainID {
+		return errors.New("only governance transactions can be posted")
+	}
+	snapshot := a.rollup.Snapshot()
+	state, err := snapshot.GetState(snapshot.GetLatestBlock().Height)
+	if err != nil {
+		return err
+	}
+	admins, err := GetAdminSet(state)
+	if err != nil {
+		return err
+	}
+	if admins == nil {
+		return errors.New("governance is disabled")
+	}
+	_, err = governanceSigners(tx, admins)
+	return err
+}
+
+// canonicalReports returns the report txs of the block which became the latest record of their chain. Reports which
+// were only counted towards a quorum aren't returned.
+func canonicalReports(block Block) []*Transaction {
+	txs := []*Transaction{}
+	for i, txBytes := range block.Txs {
+		if block.Receipts[i].Status != ReceiptAccepted {
+			continue
+		}
+		tx, err := DecodeTransaction(txBytes)
+		if err != nil {
+			log.Errorf("Failed to decode transaction: %v", err)
+			continue
+		}
+		if slices.Contains(block.Receipts[i].ChangedKeys, LatestRecordKey(tx.ChainID, tx.Finality)) {
+			txs = append(txs, tx)
+		}
+	}
+	return txs
 }
 
 func (a *App) Run() {
@@ -193,15 +722,31 @@ func (a *App) Run() {
 		}
 	}()
 
-	// run go routine which waits for eth block data from ethBlockDataRcvr
+	// run go routine which waits for reports from the chain listeners and posted txs. It is the only goroutine which
+	// sequences txs, since the sequencer client tracks the nonce of the sequencer account.
 	go func() {
 		for {
 			select {
-			case ethBlockData := <-a.ethBlockDataRcvr:
-				log.Debugf("received eth block data: %v\n", ethBlockData)
+			case submission := <-a.submissions:
+				resp, err := a.sequencerClient.SequenceTx(submission.tx)
+				if err == nil {
+					log.WithField("responseCode", resp.Code).Debug("transaction submission result")
+				}
+				submission.result <- err
+			case report := <-a.reports:
+				log.Debugf("received %s %s report: %v\n", report.Finality, report.ChainID, report.Payload)
 				// send it to the sequencer
-				tx := Transaction{
-					FinalizedEthBlockData: ethBlockData,
+				tx, err := NewTransaction(report.ChainID, report.PayloadType, report.Payload)
+				if err != nil {
+					log.Errorf("error creating transaction: %s\n", err)
+					continue
+				}
+				if report.Finality != FinalityHead {
+					tx.Finality = report.Finality
+				}
+				if err := tx.Sign(a.reporterKey); err != nil {
+					log.Errorf("error signing transaction: %s\n", err)
+					continue
 				}
 				resp, err := a.sequencerClient.SequenceTx(tx)
 				if err != nil {
@@ -215,25 +760,14 @@ func (a *App) Run() {
 
 	go func() {
 		for block := range a.newBlockChan {
-			// only write blocks with transactions
-			if len(block.Txs) == 0 {
-				continue
-			}
-
-			for _, tx := range block.Txs {
+			for _, tx := range canonicalReports(block) {
 				txJson, err := json.Marshal(tx)
 				if err != nil {
 					log.Errorf("Failed to marshal transaction: %v", err)
 					continue
 				}
 
-				for client := range a.wsClients {
-					select {
-					case client.egress <- txJson:
-					default:
-						log.Warnf("Could not send transaction to ws client: %s", txJson)
-					}
-				}
+				a.broadcastWS(txJson)
 			}
 		}
 	}()
@@ -0,0 +1,121 @@
+package rollup
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+)
+
+func newTestApp(t *testing.T, genesis *Genesis, txAPIToken string) *App {
+	t.Helper()
+	r, err := NewRollup(NewMemoryStore(), genesis, make(chan Block, 100))
+	if err != nil {
+		t.Fatal(err)
+	}
+	app := &App{
+		restRouter:  mux.NewRouter(),
+		rollup:      r,
+		genesis:     genesis,
+		wsClients:   map[*WSClient]bool{},
+		submissions: make(chan txSubmission),
+		txAPIToken:  txAPIToken,
+	}
+	app.setupRestRoutes()
+	return app
+}
+
+func postTestTx(app *App, token string, tx Transaction) int {
+	body, _ := json.Marshal(tx)
+	req := httptest.NewRequest(http.MethodPost, "/tx", bytes.NewReader(body))
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	rec := httptest.NewRecorder()
+	app.restRouter.ServeHTTP(rec, req)
+	return rec.Code
+}
+
+func TestPostTransaction(t *testing.T) {
+	admin := testReporter(10)
+	genesis := testGenesis(1, testReporter(1))
+	genesis.Admins = []string{testPublicKey(admin)}
+
+	governanceTx, err := NewGovernanceTransaction(GovernanceAction{Type: ActionAddReporter, PublicKey: testPublicKey(testReporter(2))})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := governanceTx.Sign(admin); err != nil {
+		t.Fatal(err)
+	}
+	unauthorizedTx := governanceTx
+	if err := unauthorizedTx.Sign(testReporter(11)); err != nil {
+		t.Fatal(err)
+	}
+	reportTx, err := NewEthTransaction(testEthBlock(1, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := reportTx.Sign(testReporter(1)); err != nil {
+		t.Fatal(err)
+	}
+
+	if code := postTestTx(newTestApp(t, genesis, ""), "", governanceTx); code == http.StatusOK {
+		t.Fatal("posting txs is enabled without a token")
+	}
+
+	app := newTestApp(t, genesis, "secret")
+	sequenced := make(chan Transaction, 10)
+	go func() {
+		for submission := range app.submissions {
+			sequenced <- submission.tx
+			submission.result <- nil
+		}
+	}()
+	defer close(app.submissions)
+
+	for _, c := range []struct {
+		name  string
+		token string
+		tx    Transaction
+		code  int
+	}{
+		{"missing token", "", governanceTx, http.StatusUnauthorized},
+		{"wrong token", "guess", governanceTx, http.StatusUnauthorized},
+		{"report tx", "secret", reportTx, http.StatusBadRequest},
+		{"not signed by an admin", "secret", unauthorizedTx, http.StatusBadRequest},
+		{"governance tx", "secret", governanceTx, http.StatusOK},
+	} {
+		if code := postTestTx(app, c.token, c.tx); code != c.code {
+			t.Errorf("%s: got status %d, expected %d", c.name, code, c.code)
+		}
+	}
+	if len(sequenced) != 1 {
+		t.Fatalf("sequenced %d txs, expected only the governance tx", len(sequenced))
+	}
+}
+
+func TestCheckSequencerKey(t *testing.T) {
+	sequencer := testReporter(1)
+	genesis := testGenesis(1, sequencer)
+	r, err := NewRollup(NewMemoryStore(), genesis, make(chan Block, 100))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := checkSequencerKey(r, sequencer.Public().(ed25519.PublicKey)); err != nil {
+		t.Fatalf("refused a sequencer key which is only a reporter: %s", err)
+	}
+
+	genesis.Admins = []string{testPublicKey(testReporter(2)), testPublicKey(sequencer)}
+	r, err = NewRollup(NewMemoryStore(), genesis, make(chan Block, 100))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := checkSequencerKey(r, sequencer.Public().(ed25519.PublicKey)); err == nil {
+		t.Fatal("accepted a sequencer key which is an admin")
+	}
+}
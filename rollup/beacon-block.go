@@ -0,0 +1,205 @@
+package rollup
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"slices"
+
+	"github.com/attestantio/go-eth2-client/spec"
+	"github.com/attestantio/go-eth2-client/spec/altair"
+	"github.com/attestantio/go-eth2-client/spec/bellatrix"
+	"github.com/attestantio/go-eth2-client/spec/capella"
+	"github.com/attestantio/go-eth2-client/spec/deneb"
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	ssz "github.com/ferranbt/fastssz"
+)
+
+// maxWithdrawalsPerPayload is the maximum number of withdrawals in an execution payload.
+const maxWithdrawalsPerPayload = 16
+
+// BeaconBlockResponse is the response of the beacon api's blocks endpoint. The message is decoded according to
+// the fork in Version, see Block.
+type BeaconBlockResponse struct {
+	Version             spec.DataVersion `json:"version"`
+	ExecutionOptimistic bool             `json:"execution_optimistic"`
+	Finalized           bool             `json:"finalized"`
+	Data                struct {
+		Message json.RawMessage `json:"message"`
+	} `json:"data"`
+}
+
+// json marshal and unmarshal
+func (b *BeaconBlockResponse) Marshal() ([]byte, error) {
+	return json.Marshal(b)
+}
+
+func (b *BeaconBlockResponse) Unmarshal(data []byte) error {
+	return json.Unmarshal(data, b)
+}
+
+// Block decodes the message into the beacon block type of the response's fork.
+func (b *BeaconBlockResponse) Block() (*spec.VersionedBeaconBlock, error) {
+	block := &spec.VersionedBeaconBlock{Version: b.Version}
+	var message any
+	switch b.Version {
+	case spec.DataVersionPhase0:
+		block.Phase0 = &phase0.BeaconBlock{}
+		message = block.Phase0
+	case spec.DataVersionAltair:
+		block.Altair = &altair.BeaconBlock{}
+		message = block.Altair
+	case spec.DataVersionBellatrix:
+		block.Bellatrix = &bellatrix.BeaconBlock{}
+		message = block.Bellatrix
+	case spec.DataVersionCapella:
+		block.Capella = &capella.BeaconBlock{}
+		message = block.Capella
+	case spec.DataVersionDeneb:
+		block.Deneb = &deneb.BeaconBlock{}
+		message = block.Deneb
+	default:
+		return nil, fmt.Errorf("unsupported beacon block version %s", b.Version)
+	}
+	if err := json.Unmarshal(b.Data.Message, message); err != nil {
+		return nil, fmt.Errorf("failed to decode %s beacon block: %w", b.Version, err)
+	}
+	return block, nil
+}
+
+// newEthBlockData builds the report of a beacon block of any fork. Blocks from before the merge don't have an
+// execution block, so their execution fields are left empty.
+func newEthBlockData(block *spec.VersionedBeaconBlock) (EthBlockData, error) {
+	data := EthBlockData{}
+	if err := data.setExecutionPayload(block); err != nil {
+		return EthBlockData{}, err
+	}
+
+	blockRoot, err := block.Root()
+	if err != nil {
+		return EthBlockData{}, fmt.Errorf("error computing beacon block root: %w", err)
+	}
+	parentRoot, err := block.ParentRoot()
+	if err != nil {
+		return EthBlockData{}, err
+	}
+	stateRoot, err := block.StateRoot()
+	if err != nil {
+		return EthBlockData{}, err
+	}
+	slot, err := block.Slot()
+	if err != nil {
+		return EthBlockData{}, err
+	}
+	proposerIndex, err := block.ProposerIndex()
+	if err != nil {
+		return EthBlockData{}, err
+	}
+	data.BlockRoot = blockRoot.String()
+	data.ParentRoot = parentRoot.String()
+	data.StateRoot = stateRoot.String()
+	data.Slot = uint64(slot)
+	data.ProposerIndex = uint64(proposerIndex)
+	return data, nil
+}
+
+// setExecutionPayload sets the execution fields from the execution payload of the block, if it has one.
+func (d *EthBlockData) setExecutionPayload(block *spec.VersionedBeaconBlock) error {
+	switch block.Version {
+	case spec.DataVersionPhase0:
+		if block.Phase0 == nil || block.Phase0.Body == nil {
+			return errors.New("no phase0 block body")
+		}
+		return nil
+	case spec.DataVersionAltair:
+		if block.Altair == nil || block.Altair.Body == nil {
+			return errors.New("no altair block body")
+		}
+		return nil
+	case spec.DataVersionBellatrix:
+		if block.Bellatrix == nil || block.Bellatrix.Body == nil || block.Bellatrix.Body.ExecutionPayload == nil {
+			return errors.New("no bellatrix execution payload")
+		}
+		payload := block.Bellatrix.Body.ExecutionPayload
+		if payload.BlockHash == (phase0.Hash32{}) {
+			// the payload of bellatrix blocks before the merge is empty
+			return nil
+		}
+		d.setExecutionHeader(payload.BlockHash, payload.BlockNumber, payload.StateRoot, payload.ReceiptsRoot,
+			payload.Timestamp, littleEndianToDecimal(payload.BaseFeePerGas), payload.GasUsed, payload.GasLimit)
+		return nil
+	case spec.DataVersionCapella:
+		if block.Capella == nil || block.Capella.Body == nil || block.Capella.Body.ExecutionPayload == nil {
+			return errors.New("no capella execution payload")
+		}
+		payload := block.Capella.Body.ExecutionPayload
+		d.setExecutionHeader(payload.BlockHash, payload.BlockNumber, payload.StateRoot, payload.ReceiptsRoot,
+			payload.Timestamp, littleEndianToDecimal(payload.BaseFeePerGas), payload.GasUsed, payload.GasLimit)
+		return d.setWithdrawalsRoot(payload.Withdrawals)
+	case spec.DataVersionDeneb:
+		if block.Deneb == nil || block.Deneb.Body == nil || block.Deneb.Body.ExecutionPayload == nil {
+			return errors.New("no deneb execution payload")
+		}
+		payload := block.Deneb.Body.ExecutionPayload
+		baseFee := "0"
+		if payload.BaseFeePerGas != nil {
+			baseFee = payload.BaseFeePerGas.Dec()
+		}
+		d.setExecutionHeader(payload.BlockHash, payload.BlockNumber, payload.StateRoot, payload.ReceiptsRoot,
+			payload.Timestamp, baseFee, payload.GasUsed, payload.GasLimit)
+		d.BlobGasUsed = payload.BlobGasUsed
+		d.ExcessBlobGas = payload.ExcessBlobGas
+		return d.setWithdrawalsRoot(payload.Withdrawals)
+	default:
+		return fmt.Errorf("unsupported beacon block version %s", block.Version)
+	}
+}
+
+// setExecutionHeader sets the execution fields which all forks since the merge have.
+func (d *EthBlockData) setExecutionHeader(blockHash phase0.Hash32, blockNumber uint64, stateRoot [32]byte,
+	receiptsRoot [32]byte, timestamp uint64, baseFeePerGas string, gasUsed uint64, gasLimit uint64) {
+	d.BlockHash = fmt.Sprintf("%#x", blockHash[:])
+	d.BlockNumber = blockNumber
+	d.ExecutionStateRoot = fmt.Sprintf("%#x", stateRoot[:])
+	d.ReceiptsRoot = fmt.Sprintf("%#x", receiptsRoot[:])
+	d.Timestamp = timestamp
+	d.BaseFeePerGas = baseFeePerGas
+	d.GasUsed = gasUsed
+	d.GasLimit = gasLimit
+}
+
+func (d *EthBlockData) setWithdrawalsRoot(withdrawals []*capella.Withdrawal) error {
+	root, err := withdrawalsRoot(withdrawals)
+	if err != nil {
+		return fmt.Errorf("error computing withdrawals root: %w", err)
+	}
+	d.WithdrawalsRoot = fmt.Sprintf("%#x", root)
+	return nil
+}
+
+// withdrawalsRoot returns the hash tree root of the withdrawals of an execution payload, which is the withdrawals
+// root of its execution payload header.
+func withdrawalsRoot(withdrawals []*capella.Withdrawal) ([32]byte, error) {
+	if len(withdrawals) > maxWithdrawalsPerPayload {
+		return [32]byte{}, ssz.ErrIncorrectListSize
+	}
+	hh := ssz.DefaultHasherPool.Get()
+	defer ssz.DefaultHasherPool.Put(hh)
+	index := hh.Index()
+	for _, withdrawal := range withdrawals {
+		if err := withdrawal.HashTreeRootWith(hh); err != nil {
+			return [32]byte{}, err
+		}
+	}
+	hh.MerkleizeWithMixin(index, uint64(len(withdrawals)), maxWithdrawalsPerPayload)
+	return hh.HashRoot()
+}
+
+// littleEndianToDecimal converts a little endian uint256, which is how bellatrix and capella payloads encode the
+// base fee, to a decimal string.
+func littleEndianToDecimal(value [32]byte) string {
+	bigEndian := slices.Clone(value[:])
+	slices.Reverse(bigEndian)
+	return new(big.Int).SetBytes(bigEndian).String()
+}
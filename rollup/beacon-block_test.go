@@ -0,0 +1,200 @@
+package rollup
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/attestantio/go-eth2-client/spec/bellatrix"
+	"github.com/attestantio/go-eth2-client/spec/capella"
+	"github.com/attestantio/go-eth2-client/spec/deneb"
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	ssz "github.com/ferranbt/fastssz"
+)
+
+var updateGolden = flag.Bool("update", false, "update the golden files in testdata")
+
+// readBeaconBlockFixture decodes a response of the beacon api's blocks endpoint in testdata/beacon.
+func readBeaconBlockFixture(t *testing.T, name string) *BeaconBlockResponse {
+	t.Helper()
+	raw, err := os.ReadFile(filepath.Join("testdata", "beacon", name+".json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	res := &BeaconBlockResponse{}
+	if err := res.Unmarshal(raw); err != nil {
+		t.Fatal(err)
+	}
+	return res
+}
+
+// TestNewEthBlockDataMatchesGolden builds the report of a block of each fork and compares it with the golden file
+// next to the block. Run with -update to rewrite the golden files.
+func TestNewEthBlockDataMatchesGolden(t *testing.T) {
+	for _, name := range []string{"phase0", "altair", "bellatrix-pre-merge", "bellatrix", "capella", "deneb"} {
+		t.Run(name, func(t *testing.T) {
+			block, err := readBeaconBlockFixture(t, name).Block()
+			if err != nil {
+				t.Fatal(err)
+			}
+			data, err := newEthBlockData(block)
+			if err != nil {
+				t.Fatal(err)
+			}
+			goldenPath := filepath.Join("testdata", "beacon", name+".golden.json")
+			if *updateGolden {
+				golden, err := json.MarshalIndent(data, "", "  ")
+				if err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(goldenPath, append(golden, '\n'), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			raw, err := os.ReadFile(goldenPath)
+			if err != nil {
+				t.Fatal(err)
+			}
+			expected := EthBlockData{}
+			if err := json.Unmarshal(raw, &expected); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(data, expected) {
+				t.Fatalf("got %+v, expected %+v", data, expected)
+			}
+
+			// the report is accepted by the rollup's validation
+			tx, err := NewEthTransaction(data)
+			if err != nil {
+				t.Fatal(err)
+			}
+			record := ChainRecord{ChainID: EthereumChainID, PayloadType: PayloadTypeEthBlock, ReportHeight: data.Slot, Data: tx.Payload}
+			if err := validateReport(nil, &record); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
+
+// transactionsRoot returns the hash tree root of the transactions of an execution payload, which is the
+// transactions root of its execution payload header.
+func transactionsRoot(t *testing.T, transactions []bellatrix.Transaction) phase0.Root {
+	t.Helper()
+	hh := ssz.DefaultHasherPool.Get()
+	defer ssz.DefaultHasherPool.Put(hh)
+	index := hh.Index()
+	for _, tx := range transactions {
+		txIndex := hh.Index()
+		hh.AppendBytes32(tx)
+		hh.MerkleizeWithMixin(txIndex, uint64(len(tx)), (1073741824+31)/32)
+	}
+	hh.MerkleizeWithMixin(index, uint64(len(transactions)), 1048576)
+	root, err := hh.HashRoot()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return root
+}
+
+// TestWithdrawalsRootMatchesPayloadHeader checks the withdrawals root against the execution payload header of the
+// payload: the header with the root in place of the withdrawals has the same hash tree root as the payload.
+func TestWithdrawalsRootMatchesPayloadHeader(t *testing.T) {
+	capellaBlock, err := readBeaconBlockFixture(t, "capella").Block()
+	if err != nil {
+		t.Fatal(err)
+	}
+	capellaPayload := capellaBlock.Capella.Body.ExecutionPayload
+	if len(capellaPayload.Withdrawals) == 0 {
+		t.Fatal("the capella fixture has no withdrawals")
+	}
+	capellaData, err := newEthBlockData(capellaBlock)
+	if err != nil {
+		t.Fatal(err)
+	}
+	capellaHeader := &capella.ExecutionPayloadHeader{
+		ParentHash:       capellaPayload.ParentHash,
+		FeeRecipient:     capellaPayload.FeeRecipient,
+		StateRoot:        capellaPayload.StateRoot,
+		ReceiptsRoot:     capellaPayload.ReceiptsRoot,
+		LogsBloom:        capellaPayload.LogsBloom,
+		PrevRandao:       capellaPayload.PrevRandao,
+		BlockNumber:      capellaPayload.BlockNumber,
+		GasLimit:         capellaPayload.GasLimit,
+		GasUsed:          capellaPayload.GasUsed,
+		Timestamp:        capellaPayload.Timestamp,
+		ExtraData:        capellaPayload.ExtraData,
+		BaseFeePerGas:    capellaPayload.BaseFeePerGas,
+		BlockHash:        capellaPayload.BlockHash,
+		TransactionsRoot: transactionsRoot(t, capellaPayload.Transactions),
+		WithdrawalsRoot:  parseTestRoot(t, capellaData.WithdrawalsRoot),
+	}
+	checkHeaderRoot(t, capellaHeader, capellaPayload)
+
+	denebBlock, err := readBeaconBlockFixture(t, "deneb").Block()
+	if err != nil {
+		t.Fatal(err)
+	}
+	denebPayload := denebBlock.Deneb.Body.ExecutionPayload
+	denebData, err := newEthBlockData(denebBlock)
+	if err != nil {
+		t.Fatal(err)
+	}
+	denebHeader := &deneb.ExecutionPayloadHeader{
+		ParentHash:       denebPayload.ParentHash,
+		FeeRecipient:     denebPayload.FeeRecipient,
+		StateRoot:        denebPayload.StateRoot,
+		ReceiptsRoot:     denebPayload.ReceiptsRoot,
+		LogsBloom:        denebPayload.LogsBloom,
+		PrevRandao:       denebPayload.PrevRandao,
+		BlockNumber:      denebPayload.BlockNumber,
+		GasLimit:         denebPayload.GasLimit,
+		GasUsed:          denebPayload.GasUsed,
+		Timestamp:        denebPayload.Timestamp,
+		ExtraData:        denebPayload.ExtraData,
+		BaseFeePerGas:    denebPayload.BaseFeePerGas,
+		BlockHash:        denebPayload.BlockHash,
+		TransactionsRoot: transactionsRoot(t, denebPayload.Transactions),
+		WithdrawalsRoot:  parseTestRoot(t, denebData.WithdrawalsRoot),
+		BlobGasUsed:      denebPayload.BlobGasUsed,
+		ExcessBlobGas:    denebPayload.ExcessBlobGas,
+	}
+	checkHeaderRoot(t, denebHeader, denebPayload)
+
+	// the withdrawals root of a payload without withdrawals
+	empty, err := withdrawalsRoot(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if root := formatEthHash(empty[:]); root != "0x792930bbd5baac43bcc798ee49aa8185ef76bb3b44ba62b91d86ae569e4bb535" {
+		t.Fatalf("empty withdrawals root is %s", root)
+	}
+}
+
+func parseTestRoot(t *testing.T, hash string) phase0.Root {
+	t.Helper()
+	bs, err := hex.DecodeString(strings.TrimPrefix(hash, "0x"))
+	if err != nil || len(bs) != 32 {
+		t.Fatalf("invalid root %s", hash)
+	}
+	return phase0.Root(bs)
+}
+
+func checkHeaderRoot(t *testing.T, header ssz.HashRoot, payload ssz.HashRoot) {
+	t.Helper()
+	headerRoot, err := header.HashTreeRoot()
+	if err != nil {
+		t.Fatal(err)
+	}
+	payloadRoot, err := payload.HashTreeRoot()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if headerRoot != payloadRoot {
+		t.Fatalf("header root %x doesn't match payload root %x", headerRoot, payloadRoot)
+	}
+}
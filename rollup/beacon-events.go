@@ -0,0 +1,198 @@
+package rollup
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	// beaconEventTopics are the beacon api event topics the listener subscribes to.
+	beaconEventTopics = "head,finalized_checkpoint,chain_reorg"
+	// minEventBackoff and maxEventBackoff bound how long the listener polls before reconnecting to the event stream.
+	minEventBackoff = time.Second
+	maxEventBackoff = time.Minute
+	// eventStreamIdleTimeout is how long the event stream can be silent before it is considered broken. Beacon
+	// nodes send a head event every slot.
+	eventStreamIdleTimeout = time.Minute
+)
+
+// headEvent is the data of a head event of the beacon api.
+type headEvent struct {
+	Slot            string `json:"slot"`
+	Block           string `json:"block"`
+	EpochTransition bool   `json:"epoch_transition"`
+}
+
+// finalizedCheckpointEvent is the data of a finalized_checkpoint event of the beacon api.
+type finalizedCheckpointEvent struct {
+	Block string `json:"block"`
+	Epoch string `json:"epoch"`
+}
+
+// chainReorgEvent is the data of a chain_reorg event of the beacon api.
+type chainReorgEvent struct {
+	Slot         string `json:"slot"`
+	Depth        string `json:"depth"`
+	OldHeadBlock string `json:"old_head_block"`
+	NewHeadBlock string `json:"new_head_block"`
+}
+
+// runEvents follows the event stream of the beacon node until the listener is stopped. While the stream can't be
+// opened or after it broke, the listener polls for a backoff, which doubles up to maxEventBackoff, before it
+// reconnects.
+func (l *BeaconListener) runEvents(reports chan<- Report) {
+	backoff := l.eventBackoff
+	for {
+		connected, err := l.subscribe(reports)
+		if l.ctx.Err() != nil {
+			return
+		}
+		if connected {
+			backoff = l.eventBackoff
+		}
+		l.logger.Warnf("beacon event stream failed, polling for %s before reconnecting: %s", backoff, err)
+		if !l.reportLatest(reports) || !l.poll(reports, time.After(backoff)) {
+			return
+		}
+		backoff = min(2*backoff, maxEventBackoff)
+	}
+}
+
+// subscribe opens the event stream of the preferred healthy endpoint and reports the blocks of the events until the
+// stream fails. It returns whether the stream was opened and why it ended. An endpoint whose stream can't be opened
+// is marked as failed, so that the next subscription fails over to another endpoint.
+func (l *BeaconListener) subscribe(reports chan<- Report) (bool, error) {
+	e := l.endpoints.healthy()[0]
+	connected, err := l.subscribeTo(e, reports)
+	if !connected && l.ctx.Err() == nil {
+		l.endpoints.failed(e)
+	}
+	return connected, err
+}
+
+// subscribeTo follows the event stream of an endpoint, see subscribe.
+func (l *BeaconListener) subscribeTo(e *endpoint, reports chan<- Report) (bool, error) {
+	ctx, cancel := context.WithCancel(l.ctx)
+	defer cancel()
+	idle := time.AfterFunc(l.eventIdleTimeout, cancel)
+	defer idle.Stop()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s/eth/v1/events?topics=%s", e.url, beaconEventTopics), nil)
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Accept", "text/event-stream")
+	resp, err := l.streamClient.Do(req)
+	if err != nil {
+		return false, stripURL(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return false, fmt.Errorf("unexpected status %s from %s", resp.Status, e)
+	}
+	l.endpoints.succeeded(e)
+	l.logger.Infof("subscribed to beacon events of %s", e)
+
+	// the stream only has new events, so the current block is reported first
+	if !l.reportLatest(reports) {
+		return true, l.ctx.Err()
+	}
+
+	scanner := bufio.NewScanner(resp.Body)
+	event := ""
+	data := []string{}
+	for scanner.Scan() {
+		idle.Reset(l.eventIdleTimeout)
+		line := scanner.Text()
+		switch {
+		case line == "":
+			// a blank line ends an event
+			if len(data) > 0 && !l.handleEvent(reports, event, strings.Join(data, "\n")) {
+				return true, l.ctx.Err()
+			}
+			event = ""
+			data = []string{}
+		case strings.HasPrefix(line, ":"):
+			// comments are used as keep alives
+		default:
+			field, value, _ := strings.Cut(line, ":")
+			value = strings.TrimPrefix(value, " ")
+			switch field {
+			case "event":
+				event = value
+			case "data":
+				data = append(data, value)
+			}
+		}
+	}
+	if ctx.Err() != nil && l.ctx.Err() == nil {
+		return true, fmt.Errorf("no events for %s", l.eventIdleTimeout)
+	}
+	if err := scanner.Err(); err != nil {
+		return true, err
+	}
+	return true, errors.New("event stream closed")
+}
+
+// handleEvent reports the block an event is about, if it is about a block of the listener's finality level.
+// It returns false if the listener was stopped.
+func (l *BeaconListener) handleEvent(reports chan<- Report, event string, data string) bool {
+	blockID, err := l.eventBlockID(event, []byte(data))
+	if err != nil {
+		l.logger.Errorf("error handling %s event: %s", event, err)
+		return l.ctx.Err() == nil
+	}
+	if blockID == "" {
+		return true
+	}
+	ethBlockData, err := l.fetchBlockByID(blockID)
+	if err != nil {
+		l.logger.Errorf("error fetching beacon block %s: %s", blockID, err)
+		return l.ctx.Err() == nil
+	}
+	return l.report(reports, ethBlockData)
+}
+
+// eventBlockID returns the id of the block of the listener's finality level an event is about, or an empty id if the
+// event isn't relevant for the listener. Head listeners follow head and chain_reorg events and finalized listeners
+// finalized_checkpoint events. The justified checkpoint can only change at an epoch transition, so justified
+// listeners look it up on head events which start an epoch.
+func (l *BeaconListener) eventBlockID(event string, data []byte) (string, error) {
+	switch {
+	case event == "head" && l.finality == FinalityHead:
+		head := headEvent{}
+		if err := json.Unmarshal(data, &head); err != nil {
+			return "", err
+		}
+		return head.Block, nil
+	case event == "chain_reorg" && l.finality == FinalityHead:
+		reorg := chainReorgEvent{}
+		if err := json.Unmarshal(data, &reorg); err != nil {
+			return "", err
+		}
+		l.logger.Warnf("chain reorg of depth %s at slot %s from %s to %s", reorg.Depth, reorg.Slot, reorg.OldHeadBlock, reorg.NewHeadBlock)
+		return reorg.NewHeadBlock, nil
+	case event == "finalized_checkpoint" && l.finality == FinalityFinalized:
+		checkpoint := finalizedCheckpointEvent{}
+		if err := json.Unmarshal(data, &checkpoint); err != nil {
+			return "", err
+		}
+		return checkpoint.Block, nil
+	case event == "head" && l.finality == FinalityJustified:
+		head := headEvent{}
+		if err := json.Unmarshal(data, &head); err != nil {
+			return "", err
+		}
+		if !head.EpochTransition {
+			return "", nil
+		}
+		return l.justifiedBlockID()
+	}
+	return "", nil
+}
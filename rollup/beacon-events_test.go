@@ -0,0 +1,323 @@
+package rollup
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// testBeaconNode serves the beacon api endpoints the listener uses: blocks by slot, root or head, and the event
+// stream. The blocks are the phase0 fixture with the slot and parent root changed.
+type testBeaconNode struct {
+	t      *testing.T
+	server *httptest.Server
+
+	lock   sync.Mutex
+	blocks map[string][]byte
+	roots  map[uint64]string
+	head   uint64
+	// eventStatus is the status event stream requests are answered with. The stream is only opened with 200.
+	eventStatus   int
+	eventRequests []time.Time
+	// events are written to the open event stream.
+	events chan string
+}
+
+func newTestBeaconNode(t *testing.T) *testBeaconNode {
+	node := &testBeaconNode{
+		t:           t,
+		blocks:      map[string][]byte{},
+		roots:       map[uint64]string{},
+		eventStatus: http.StatusOK,
+		events:      make(chan string, 10),
+	}
+	node.server = httptest.NewServer(http.HandlerFunc(node.serveHTTP))
+	t.Cleanup(node.server.Close)
+	return node
+}
+
+// addBlock adds a block at the slot, which builds on the block at the parent slot, and returns its root.
+func (n *testBeaconNode) addBlock(slot uint64, parentSlot uint64) string {
+	n.t.Helper()
+	raw, err := os.ReadFile(filepath.Join("testdata", "beacon", "phase0.json"))
+	if err != nil {
+		n.t.Fatal(err)
+	}
+	res := map[string]any{}
+	if err := json.Unmarshal(raw, &res); err != nil {
+		n.t.Fatal(err)
+	}
+	message := res["data"].(map[string]any)["message"].(map[string]any)
+	message["slot"] = strconv.FormatUint(slot, 10)
+	n.lock.Lock()
+	if parentRoot, ok := n.roots[parentSlot]; ok {
+		message["parent_root"] = parentRoot
+	}
+	n.lock.Unlock()
+	raw, err = json.Marshal(res)
+	if err != nil {
+		n.t.Fatal(err)
+	}
+
+	block := &BeaconBlockResponse{}
+	if err := block.Unmarshal(raw); err != nil {
+		n.t.Fatal(err)
+	}
+	versioned, err := block.Block()
+	if err != nil {
+		n.t.Fatal(err)
+	}
+	root, err := versioned.Root()
+	if err != nil {
+		n.t.Fatal(err)
+	}
+
+	n.lock.Lock()
+	defer n.lock.Unlock()
+	n.roots[slot] = root.String()
+	n.blocks[strconv.FormatUint(slot, 10)] = raw
+	n.blocks[root.String()] = raw
+	return root.String()
+}
+
+func (n *testBeaconNode) setHead(slot uint64) {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+	n.head = slot
+}
+
+func (n *testBeaconNode) setEventStatus(status int) {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+	n.eventStatus = status
+}
+
+func (n *testBeaconNode) eventRequestTimes() []time.Time {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+	return append([]time.Time{}, n.eventRequests...)
+}
+
+func (n *testBeaconNode) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path == "/eth/v1/events" {
+		n.serveEvents(w, r)
+		return
+	}
+	blockID, ok := strings.CutPrefix(r.URL.Path, "/eth/v2/beacon/blocks/")
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	n.lock.Lock()
+	if blockID == "head" {
+		blockID = strconv.FormatUint(n.head, 10)
+	}
+	block, ok := n.blocks[blockID]
+	n.lock.Unlock()
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	w.Write(block)
+}
+
+func (n *testBeaconNode) serveEvents(w http.ResponseWriter, r *http.Request) {
+	n.lock.Lock()
+	n.eventRequests = append(n.eventRequests, time.Now())
+	status := n.eventStatus
+	n.lock.Unlock()
+	if status != http.StatusOK {
+		w.WriteHeader(status)
+		return
+	}
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.WriteHeader(http.StatusOK)
+	w.(http.Flusher).Flush()
+	for {
+		select {
+		case event := <-n.events:
+			fmt.Fprint(w, event)
+			w.(http.Flusher).Flush()
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
+
+// newTestEventListener returns a head listener in events mode following the node, which isn't started.
+func newTestEventListener(t *testing.T, node *testBeaconNode) *BeaconListener {
+	t.Helper()
+	l := newTestBeaconListener(t, node.server.URL, FinalityHead)
+	l.mode = ListenerModeEvents
+	return l
+}
+
+// nextReportSlot waits for the next report and returns its slot.
+func nextReportSlot(t *testing.T, reports chan Report) uint64 {
+	t.Helper()
+	select {
+	case report := <-reports:
+		return report.Payload.(*EthBlockData).Slot
+	case <-time.After(5 * time.Second):
+		t.Fatal("no report")
+		return 0
+	}
+}
+
+func headEventData(slot uint64, root string) string {
+	return fmt.Sprintf(`{"slot":"%d","block":"%s","epoch_transition":false}`, slot, root)
+}
+
+func TestBeaconListenerFollowsEvents(t *testing.T) {
+	node := newTestBeaconNode(t)
+	node.addBlock(1, 0)
+	root2 := node.addBlock(2, 1)
+	root3 := node.addBlock(3, 1)
+	node.setHead(1)
+
+	l := newTestEventListener(t, node)
+	reports := make(chan Report, 10)
+	if err := l.Start(reports); err != nil {
+		t.Fatal(err)
+	}
+	defer l.Stop()
+
+	// the current head is reported when the stream is opened
+	if slot := nextReportSlot(t, reports); slot != 1 {
+		t.Fatalf("reported slot %d, expected the head at subscription", slot)
+	}
+	node.events <- ": keep alive\n\n"
+	// the data of an event may be split over several lines
+	data := headEventData(2, root2)
+	node.events <- "event: head\ndata: " + data[:20] + "\ndata: " + data[20:] + "\n\n"
+	if slot := nextReportSlot(t, reports); slot != 2 {
+		t.Fatalf("reported slot %d, expected the head event", slot)
+	}
+	// events of other finality levels and unknown events are ignored
+	node.events <- fmt.Sprintf("event: finalized_checkpoint\ndata: {\"block\":\"%s\",\"epoch\":\"1\"}\n\n", root3)
+	node.events <- "event: block\ndata: {}\n\n"
+	node.events <- fmt.Sprintf("event: chain_reorg\ndata: {\"slot\":\"3\",\"depth\":\"1\",\"old_head_block\":\"%s\",\"new_head_block\":\"%s\"}\n\n", root2, root3)
+	if slot := nextReportSlot(t, reports); slot != 3 {
+		t.Fatalf("reported slot %d, expected the new head of the reorg", slot)
+	}
+	select {
+	case report := <-reports:
+		t.Fatalf("unexpected report %+v", report.Payload)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestBeaconListenerEventBlockID(t *testing.T) {
+	logger := logrus.WithField("chain", EthereumChainID)
+	head := &BeaconListener{finality: FinalityHead, logger: logger}
+	finalized := &BeaconListener{finality: FinalityFinalized, logger: logger}
+	justified := &BeaconListener{finality: FinalityJustified, logger: logger}
+	for _, c := range []struct {
+		listener *BeaconListener
+		event    string
+		data     string
+		blockID  string
+	}{
+		{head, "head", headEventData(1, "0x01"), "0x01"},
+		{head, "finalized_checkpoint", `{"block":"0x02","epoch":"1"}`, ""},
+		{finalized, "finalized_checkpoint", `{"block":"0x02","epoch":"1"}`, "0x02"},
+		{finalized, "head", headEventData(1, "0x01"), ""},
+		// justified listeners look the checkpoint up at epoch transitions only
+		{justified, "head", headEventData(1, "0x01"), ""},
+		{head, "chain_reorg", `{"slot":"1","depth":"1","old_head_block":"0x01","new_head_block":"0x03"}`, "0x03"},
+	} {
+		blockID, err := c.listener.eventBlockID(c.event, []byte(c.data))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if blockID != c.blockID {
+			t.Errorf("%s listener: %s event has block %q, expected %q", c.listener.finality, c.event, blockID, c.blockID)
+		}
+	}
+	if _, err := head.eventBlockID("head", []byte("{")); err == nil {
+		t.Fatal("parsed invalid event data")
+	}
+}
+
+func TestBeaconListenerEventStreamIdleTimeout(t *testing.T) {
+	node := newTestBeaconNode(t)
+	node.addBlock(1, 0)
+	node.setHead(1)
+
+	l := newTestEventListener(t, node)
+	l.eventIdleTimeout = 50 * time.Millisecond
+	reports := make(chan Report, 10)
+	start := time.Now()
+	connected, err := l.subscribe(reports)
+	if !connected || err == nil || !strings.Contains(err.Error(), "no events") {
+		t.Fatalf("subscription ended with %v, %v, expected the idle timeout", connected, err)
+	}
+	if elapsed := time.Since(start); elapsed < l.eventIdleTimeout {
+		t.Fatalf("subscription ended after %s, before the idle timeout", elapsed)
+	}
+	if slot := nextReportSlot(t, reports); slot != 1 {
+		t.Fatalf("reported slot %d, expected the head at subscription", slot)
+	}
+}
+
+func TestBeaconListenerPollsWhileEventStreamFails(t *testing.T) {
+	node := newTestBeaconNode(t)
+	node.addBlock(1, 0)
+	node.addBlock(2, 1)
+	node.addBlock(3, 2)
+	node.setHead(1)
+	node.setEventStatus(http.StatusServiceUnavailable)
+
+	l := newTestEventListener(t, node)
+	l.pollInterval = 10 * time.Millisecond
+	l.eventBackoff = 40 * time.Millisecond
+	reports := make(chan Report, 10)
+	if err := l.Start(reports); err != nil {
+		t.Fatal(err)
+	}
+	defer l.Stop()
+
+	// the listener falls back to polling
+	if slot := nextReportSlot(t, reports); slot != 1 {
+		t.Fatalf("reported slot %d, expected the polled head", slot)
+	}
+	node.setHead(2)
+	if slot := nextReportSlot(t, reports); slot != 2 {
+		t.Fatalf("reported slot %d, expected the polled head", slot)
+	}
+
+	// and reconnects after a backoff which doubles with each failure
+	for len(node.eventRequestTimes()) < 4 {
+		time.Sleep(10 * time.Millisecond)
+	}
+	requests := node.eventRequestTimes()
+	backoff := l.eventBackoff
+	for i := 1; i < 4; i++ {
+		if gap := requests[i].Sub(requests[i-1]); gap < backoff {
+			t.Fatalf("reconnect %d after %s, expected a backoff of %s", i, gap, backoff)
+		}
+		backoff *= 2
+	}
+
+	// once the stream is back, the listener follows its events
+	node.setEventStatus(http.StatusOK)
+	failedRequests := len(node.eventRequestTimes())
+	for len(node.eventRequestTimes()) == failedRequests {
+		time.Sleep(10 * time.Millisecond)
+	}
+	root4 := node.addBlock(4, 3)
+	node.events <- "event: head\ndata: " + headEventData(4, root4) + "\n\n"
+	if slot := nextReportSlot(t, reports); slot != 4 {
+		t.Fatalf("reported slot %d, expected the head event", slot)
+	}
+}
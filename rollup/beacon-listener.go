@@ -0,0 +1,455 @@
+package rollup
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/sirupsen/logrus"
+)
+
+type EthBlockData struct {
+	// BlockRoot is the hash tree root of the beacon block, which the parent root of the next block links to.
+	BlockRoot string `json:"block_root"`
+	// BlockHash is the hash of the execution block of the slot. It is empty for blocks before the merge.
+	BlockHash string `json:"block_hash"`
+	// StateRoot is the beacon state root.
+	StateRoot     string `json:"state_root"`
+	ParentRoot    string `json:"parent_root"`
+	Slot          uint64 `json:"slot"`
+	ProposerIndex uint64 `json:"proposer_index"`
+
+	// The remaining fields are from the execution payload of the block. They are empty in older reports.
+	BlockNumber        uint64 `json:"block_number,omitempty"`
+	ExecutionStateRoot string `json:"execution_state_root,omitempty"`
+	ReceiptsRoot       string `json:"receipts_root,omitempty"`
+	Timestamp          uint64 `json:"timestamp,omitempty"`
+	// BaseFeePerGas is the decimal base fee in wei.
+	BaseFeePerGas   string `json:"base_fee_per_gas,omitempty"`
+	GasUsed         uint64 `json:"gas_used,omitempty"`
+	GasLimit        uint64 `json:"gas_limit,omitempty"`
+	WithdrawalsRoot string `json:"withdrawals_root,omitempty"`
+	BlobGasUsed     uint64 `json:"blob_gas_used,omitempty"`
+	ExcessBlobGas   uint64 `json:"excess_blob_gas,omitempty"`
+}
+
+// errBeaconNotFound is returned for requests the beacon api answers with 404, e.g. for blocks of empty slots.
+var errBeaconNotFound = errors.New("not found")
+
+// beaconHeaderResponse is the response of the beacon api's block headers endpoint.
+type beaconHeaderResponse struct {
+	Data struct {
+		Root phase0.Root `json:"root"`
+	} `json:"data"`
+}
+
+// finalityCheckpointsResponse is the response of the beacon api's finality checkpoints of a state.
+type finalityCheckpointsResponse struct {
+	Data struct {
+		PreviousJustified *phase0.Checkpoint `json:"previous_justified"`
+		CurrentJustified  *phase0.Checkpoint `json:"current_justified"`
+		Finalized         *phase0.Checkpoint `json:"finalized"`
+	} `json:"data"`
+}
+
+// BeaconListener follows an ethereum beacon node and reports the latest block of its finality level: the head
+// block, the block of the current justified checkpoint or the finalized block. In poll mode it polls the beacon node,
+// in events mode it subscribes to the node's event stream, see runEvents.
+type BeaconListener struct {
+	chainID string
+	// endpoints are the beacon nodes, see get for how requests fail over between them.
+	endpoints *endpointPool
+	// minAgreement is the number of endpoints which have to agree on a block before it is reported, see crossCheck.
+	minAgreement int
+	pollInterval time.Duration
+	finality     Finality
+	mode         ListenerMode
+	client       *http.Client
+	// streamClient is used for the event stream, which stays open, so it has no timeout.
+	streamClient *http.Client
+	logger       *logrus.Entry
+	maxBackfill  uint64
+	// statePath is where the last report is persisted, see beaconListenerState.
+	statePath string
+	// lastRoot and lastSlot are the block root and slot of the last report. Only blocks above lastSlot are reported.
+	lastRoot string
+	lastSlot uint64
+	ctx      context.Context
+	cancel   context.CancelFunc
+	done     chan struct{}
+
+	// eventIdleTimeout and eventBackoff are eventStreamIdleTimeout and minEventBackoff, except in tests.
+	eventIdleTimeout time.Duration
+	eventBackoff     time.Duration
+}
+
+// NewBeaconListener creates a beacon listener, the rpcs are the urls of the beacon node apis.
+func NewBeaconListener(cfg ListenerConfig) (ChainListener, error) {
+	endpoints, err := newEndpointPool(cfg.Rpcs)
+	if err != nil {
+		return nil, fmt.Errorf("invalid rpc for listener %s: %w", cfg.Name, err)
+	}
+	if cfg.CrossCheck < 0 || cfg.CrossCheck > len(cfg.Rpcs) {
+		return nil, fmt.Errorf("cross check of %d endpoints for listener %s, which has %d", cfg.CrossCheck, cfg.Name, len(cfg.Rpcs))
+	}
+	if cfg.PollInterval <= 0 {
+		return nil, fmt.Errorf("invalid poll interval %s for listener %s", cfg.PollInterval, cfg.Name)
+	}
+	if cfg.Mode != ListenerModePoll && cfg.Mode != ListenerModeEvents {
+		return nil, fmt.Errorf("unsupported mode %s for listener %s", cfg.Mode, cfg.Name)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	finality := cfg.Finality.orHead()
+	l := &BeaconListener{
+		chainID:      cfg.ChainID,
+		endpoints:    endpoints,
+		minAgreement: cfg.CrossCheck,
+		pollInterval: cfg.PollInterval,
+		finality:     finality,
+		mode:         cfg.Mode,
+		client:       &http.Client{Timeout: cfg.PollInterval},
+		streamClient: &http.Client{},
+		logger:       logrus.WithField("chain", cfg.ChainID).WithField("finality", finality),
+		maxBackfill:  cfg.MaxBackfill,
+		statePath:    cfg.StatePath(),
+		ctx:          ctx,
+		cancel:       cancel,
+		done:         make(chan struct{}),
+
+		eventIdleTimeout: eventStreamIdleTimeout,
+		eventBackoff:     minEventBackoff,
+	}
+	if err := l.loadState(); err != nil {
+		cancel()
+		return nil, fmt.Errorf("failed to load state of listener %s: %w", cfg.Name, err)
+	}
+	return l, nil
+}
+
+func (l *BeaconListener) ChainID() string {
+	return l.chainID
+}
+
+func (l *BeaconListener) Start(reports chan<- Report) error {
+	go func() {
+		defer close(l.done)
+		if l.mode == ListenerModeEvents {
+			l.runEvents(reports)
+		} else {
+			l.poll(reports, nil)
+		}
+		l.logger.Debug("Shutting ethereum chain listener down!")
+	}()
+	return nil
+}
+
+func (l *BeaconListener) Stop() {
+	l.cancel()
+	<-l.done
+}
+
+// poll reports the latest block every poll interval until the listener is stopped or until is closed. It returns
+// false if the listener was stopped.
+func (l *BeaconListener) poll(reports chan<- Report, until <-chan time.Time) bool {
+	ticker := time.NewTicker(l.pollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			if !l.reportLatest(reports) {
+				return false
+			}
+		case <-until:
+			return true
+		case <-l.ctx.Done():
+			return false
+		}
+	}
+}
+
+// reportLatest fetches and reports the latest block. It returns false if the listener was stopped.
+func (l *BeaconListener) reportLatest(reports chan<- Report) bool {
+	ethBlockData, err := l.fetchBlock()
+	if l.ctx.Err() != nil {
+		return false
+	}
+	if err != nil {
+		l.logger.Errorf("error fetching beacon block: %s", err)
+		return true
+	}
+	return l.report(reports, ethBlockData)
+}
+
+// report sends a report of the block unless its slot isn't above the last reported slot, e.g. the new head of a
+// chain_reorg event. A second report for a slot would conflict with the first one, so the new head is reported with
+// the next block above it, which the rollup sees as a reorg by its parent root. The blocks of the slots missed since
+// the last report are reported first, see backfill. It returns false if the listener was stopped.
+func (l *BeaconListener) report(reports chan<- Report, ethBlockData EthBlockData) bool {
+	if ethBlockData.BlockRoot == l.lastRoot {
+		return true
+	}
+	if l.lastRoot != "" && ethBlockData.Slot <= l.lastSlot {
+		l.logger.Debugf("not reporting block %s at slot %d, which isn't above the last reported slot %d",
+			ethBlockData.BlockRoot, ethBlockData.Slot, l.lastSlot)
+		return true
+	}
+	if err := l.backfill(reports, ethBlockData.Slot); err != nil {
+		if l.ctx.Err() != nil {
+			return false
+		}
+		// the block isn't reported, since the rollup wouldn't accept the missed slots after it
+		l.logger.Errorf("error backfilling slots before slot %d: %s", ethBlockData.Slot, err)
+		return true
+	}
+	if err := l.crossCheck(ethBlockData); err != nil {
+		if l.ctx.Err() != nil {
+			return false
+		}
+		// the block is reported once enough endpoints have it, with the next poll or event
+		l.logger.Errorf("not reporting block %s: %s", ethBlockData.BlockRoot, err)
+		return true
+	}
+	return l.send(reports, ethBlockData)
+}
+
+// backfill reports the blocks of the slots between the last reported slot and the given slot, at most maxBackfill
+// of them. Empty slots are skipped, while an error fetching a slot aborts the backfill, so that it is retried with
+// the next block.
+func (l *BeaconListener) backfill(reports chan<- Report, slot uint64) error {
+	if l.lastRoot == "" || l.maxBackfill == 0 || slot <= l.lastSlot+1 {
+		return nil
+	}
+	from := l.lastSlot + 1
+	if slot-from > l.maxBackfill {
+		l.logger.Warnf("not backfilling slots %d to %d, which are beyond the max backfill depth", from, slot-l.maxBackfill-1)
+		from = slot - l.maxBackfill
+	}
+	for backfillSlot := from; backfillSlot < slot; backfillSlot++ {
+		ethBlockData, err := l.fetchBlockByID(strconv.FormatUint(backfillSlot, 10))
+		if errors.Is(err, errBeaconNotFound) {
+			l.logger.Debugf("slot %d is empty", backfillSlot)
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("error fetching slot %d: %w", backfillSlot, err)
+		}
+		if err := l.crossCheck(ethBlockData); err != nil {
+			return fmt.Errorf("error cross-checking slot %d: %w", backfillSlot, err)
+		}
+		if !l.send(reports, ethBlockData) {
+			return l.ctx.Err()
+		}
+	}
+	return nil
+}
+
+// send sends a report of the block and persists it as the last report. It returns false if the listener was stopped.
+func (l *BeaconListener) send(reports chan<- Report, ethBlockData EthBlockData) bool {
+	l.logger.Debugf("ethBlockData is %+v", ethBlockData)
+
+	report := Report{
+		ChainID:     l.chainID,
+		PayloadType: PayloadTypeEthBlock,
+		Payload:     &ethBlockData,
+		Finality:    l.finality,
+	}
+	select {
+	case reports <- report:
+		l.lastRoot = ethBlockData.BlockRoot
+		l.lastSlot = ethBlockData.Slot
+		if err := l.saveState(); err != nil {
+			l.logger.Errorf("error saving listener state: %s", err)
+		}
+		return true
+	case <-l.ctx.Done():
+		return false
+	}
+}
+
+// beaconListenerState is the last report of a beacon listener, which is persisted so that the listener can backfill
+// the slots it missed while it was down.
+type beaconListenerState struct {
+	Slot      uint64 `json:"slot"`
+	BlockRoot string `json:"block_root"`
+}
+
+func (l *BeaconListener) loadState() error {
+	if l.statePath == "" {
+		return nil
+	}
+	data, err := os.ReadFile(l.statePath)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	state := beaconListenerState{}
+	if err := json.Unmarshal(data, &state); err != nil {
+		return err
+	}
+	l.lastSlot = state.Slot
+	l.lastRoot = state.BlockRoot
+	return nil
+}
+
+// saveState persists the last report, replacing the state file atomically.
+func (l *BeaconListener) saveState() error {
+	if l.statePath == "" {
+		return nil
+	}
+	data, err := json.Marshal(beaconListenerState{Slot: l.lastSlot, BlockRoot: l.lastRoot})
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(l.statePath), 0o755); err != nil {
+		return err
+	}
+	tmp := l.statePath + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, l.statePath)
+}
+
+// fetchBlock gets the latest block of the listener's finality level from the beacon node. The justified block is
+// looked up by the root of the current justified checkpoint of the head state.
+func (l *BeaconListener) fetchBlock() (EthBlockData, error) {
+	blockID := string(l.finality)
+	if l.finality == FinalityJustified {
+		var err error
+		if blockID, err = l.justifiedBlockID(); err != nil {
+			return EthBlockData{}, err
+		}
+	}
+	return l.fetchBlockByID(blockID)
+}
+
+// justifiedBlockID returns the root of the current justified checkpoint of the head state.
+func (l *BeaconListener) justifiedBlockID() (string, error) {
+	checkpoints := finalityCheckpointsResponse{}
+	if err := l.get("/eth/v1/beacon/states/head/finality_checkpoints", &checkpoints); err != nil {
+		return "", err
+	}
+	if checkpoints.Data.CurrentJustified == nil {
+		return "", fmt.Errorf("missing current justified checkpoint")
+	}
+	return checkpoints.Data.CurrentJustified.Root.String(), nil
+}
+
+// fetchBlockByID gets a block by its root, slot or one of the named block ids, e.g. head.
+func (l *BeaconListener) fetchBlockByID(blockID string) (EthBlockData, error) {
+	beaconBlockRes := BeaconBlockResponse{}
+	if err := l.get(fmt.Sprintf("/eth/v2/beacon/blocks/%s", blockID), &beaconBlockRes); err != nil {
+		return EthBlockData{}, err
+	}
+
+	block, err := beaconBlockRes.Block()
+	if err != nil {
+		return EthBlockData{}, err
+	}
+	return newEthBlockData(block)
+}
+
+// crossCheck checks that at least minAgreement endpoints have the block at its slot. Endpoints are asked for the
+// block root of the slot rather than the block they consider the latest, since they may be at different heads.
+// Each endpoint which disagrees is logged.
+func (l *BeaconListener) crossCheck(ethBlockData EthBlockData) error {
+	if l.minAgreement <= 1 {
+		return nil
+	}
+	agreeing := 0
+	for _, e := range l.endpoints.all() {
+		header := beaconHeaderResponse{}
+		err := l.getFrom(e, fmt.Sprintf("/eth/v1/beacon/headers/%d", ethBlockData.Slot), &header)
+		if l.ctx.Err() != nil {
+			return l.ctx.Err()
+		}
+		switch {
+		case errors.Is(err, errBeaconNotFound):
+			l.logger.Warnf("endpoint %s has no block at slot %d, expected %s", e, ethBlockData.Slot, ethBlockData.BlockRoot)
+		case err != nil:
+			l.endpoints.failed(e)
+			l.logger.Warnf("error cross-checking slot %d with endpoint %s: %s", ethBlockData.Slot, e, err)
+		case header.Data.Root.String() != ethBlockData.BlockRoot:
+			l.logger.Warnf("endpoint %s has block %s at slot %d, expected %s", e, header.Data.Root, ethBlockData.Slot, ethBlockData.BlockRoot)
+		default:
+			agreeing++
+		}
+	}
+	if agreeing < l.minAgreement {
+		return fmt.Errorf("%d of %d endpoints have block %s at slot %d, %d required",
+			agreeing, len(l.endpoints.all()), ethBlockData.BlockRoot, ethBlockData.Slot, l.minAgreement)
+	}
+	return nil
+}
+
+// get makes a GET request to the beacon api and unmarshals the JSON response into v. The request goes to the
+// healthy endpoints in turn until one answers, an endpoint which fails is skipped for a backoff. A 404 usually means
+// that the slot is empty, but the endpoint may also be behind or have pruned the block, so errBeaconNotFound is only
+// returned once all healthy endpoints answered 404, or minAgreement of them when blocks are cross-checked and enough
+// endpoints are healthy. Endpoints in backoff aren't waited for, so that an empty slot doesn't stall the listener
+// while a node is down.
+func (l *BeaconListener) get(path string, v any) error {
+	healthy := l.endpoints.healthy()
+	required := len(healthy)
+	if l.minAgreement > 1 && l.minAgreement <= len(healthy) {
+		required = l.minAgreement
+	}
+	errs := []error{}
+	notFound := 0
+	for _, e := range healthy {
+		err := l.getFrom(e, path, v)
+		if err == nil {
+			l.endpoints.succeeded(e)
+			return nil
+		}
+		if l.ctx.Err() != nil {
+			return err
+		}
+		if errors.Is(err, errBeaconNotFound) {
+			if notFound++; notFound >= required {
+				return err
+			}
+			// not wrapped, so that the joined error isn't errBeaconNotFound unless enough endpoints answered 404
+			errs = append(errs, fmt.Errorf("%s: %s", e, err))
+			continue
+		}
+		l.endpoints.failed(e)
+		l.logger.Warnf("beacon endpoint %s failed: %s", e, err)
+		errs = append(errs, fmt.Errorf("%s: %w", e, err))
+	}
+	return errors.Join(errs...)
+}
+
+// getFrom makes a GET request to an endpoint and unmarshals the JSON response into v.
+func (l *BeaconListener) getFrom(e *endpoint, path string, v any) error {
+	req, err := http.NewRequestWithContext(l.ctx, http.MethodGet, e.url+path, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := l.client.Do(req)
+	if err != nil {
+		return stripURL(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return fmt.Errorf("%w: %s", errBeaconNotFound, path)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %s from %s", resp.Status, path)
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(body, v)
+}
@@ -0,0 +1,175 @@
+package rollup
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// newTestBeaconListener returns a listener of the given finality for the beacon api at url, which isn't started.
+func newTestBeaconListener(t *testing.T, url string, finality Finality) *BeaconListener {
+	t.Helper()
+	listener, err := NewBeaconListener(ListenerConfig{
+		Name:         "ethereum",
+		ChainID:      EthereumChainID,
+		Rpcs:         []string{url},
+		PollInterval: time.Second,
+		Finality:     finality,
+		Mode:         ListenerModePoll,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	l := listener.(*BeaconListener)
+	// the listener isn't started, so it is only cancelled
+	t.Cleanup(l.cancel)
+	return l
+}
+
+// receivedSlots returns the slots of the reports sent so far.
+func receivedSlots(reports chan Report) []uint64 {
+	slots := []uint64{}
+	for {
+		select {
+		case report := <-reports:
+			slots = append(slots, report.Payload.(*EthBlockData).Slot)
+		default:
+			return slots
+		}
+	}
+}
+
+func TestBeaconListenerOnlyReportsAboveLastSlot(t *testing.T) {
+	l := newTestBeaconListener(t, "http://127.0.0.1:1", FinalityHead)
+	reports := make(chan Report, 10)
+
+	if !l.report(reports, testEthBlock(5, 4)) || !l.report(reports, testEthBlock(5, 4)) {
+		t.Fatal("listener stopped")
+	}
+	// a reorg to another block at the last slot or below it isn't reported, it would conflict with the report of
+	// the slot
+	if !l.report(reports, testEthBlockVariant(5, 4)) || !l.report(reports, testEthBlockVariant(4, 3)) {
+		t.Fatal("listener stopped")
+	}
+	// the next block of the new head is
+	if !l.report(reports, EthBlockData{
+		BlockRoot:  testHash("block", 6),
+		StateRoot:  testHash("state", 6),
+		ParentRoot: testEthBlockVariant(5, 4).BlockRoot,
+		Slot:       6,
+	}) {
+		t.Fatal("listener stopped")
+	}
+	if slots := receivedSlots(reports); len(slots) != 2 || slots[0] != 5 || slots[1] != 6 {
+		t.Fatalf("reported slots %v, expected 5 and 6", slots)
+	}
+}
+
+// newStatusServer returns a server which answers all requests with the status.
+func newStatusServer(t *testing.T, status int) string {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(server.Close)
+	return server.URL
+}
+
+func TestBeaconListenerGetFailsOverOnNotFound(t *testing.T) {
+	node := newTestBeaconNode(t)
+	node.addBlock(1, 0)
+	notFound := newStatusServer(t, http.StatusNotFound)
+	unavailable := newStatusServer(t, http.StatusServiceUnavailable)
+
+	for _, c := range []struct {
+		name       string
+		rpcs       []string
+		crossCheck int
+		// down are the indexes of the endpoints which are in backoff
+		down []int
+		// found is whether the block is returned, otherwise notFound is whether the slot is empty
+		found    bool
+		notFound bool
+	}{
+		{"an endpoint which is behind", []string{notFound, node.server.URL}, 0, nil, true, false},
+		{"all endpoints 404", []string{notFound, notFound}, 0, nil, false, true},
+		{"404 and a failure", []string{notFound, unavailable}, 0, nil, false, false},
+		{"cross-check endpoints 404", []string{notFound, notFound, node.server.URL}, 2, nil, false, true},
+		{"fewer than the cross-check endpoints 404", []string{notFound, unavailable, node.server.URL}, 2, nil, true, false},
+		{"all healthy endpoints 404", []string{unavailable, notFound}, 0, []int{0}, false, true},
+		{"cross-check endpoints 404 while one is down", []string{unavailable, notFound, notFound}, 2, []int{0}, false, true},
+		{"fewer healthy endpoints than the cross-check", []string{unavailable, notFound}, 2, []int{0}, false, true},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			listener, err := NewBeaconListener(ListenerConfig{
+				Name:         "ethereum",
+				ChainID:      EthereumChainID,
+				Rpcs:         c.rpcs,
+				PollInterval: time.Second,
+				Mode:         ListenerModePoll,
+				CrossCheck:   c.crossCheck,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			l := listener.(*BeaconListener)
+			t.Cleanup(l.cancel)
+			for _, i := range c.down {
+				l.endpoints.failed(l.endpoints.all()[i])
+			}
+
+			data, err := l.fetchBlockByID("1")
+			if c.found {
+				if err != nil || data.Slot != 1 {
+					t.Fatalf("got %+v, %v, expected the block", data, err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatal("got a block, expected an error")
+			}
+			if errors.Is(err, errBeaconNotFound) != c.notFound {
+				t.Fatalf("got %v, expected not found to be %v", err, c.notFound)
+			}
+		})
+	}
+}
+
+func TestBeaconListenerPersistsStateInItsDataDir(t *testing.T) {
+	dataDir := t.TempDir()
+	cfg := ListenerConfig{
+		Name:         "mainnet",
+		DataDir:      dataDir,
+		ChainID:      EthereumChainID,
+		Rpcs:         []string{"http://127.0.0.1:1"},
+		PollInterval: time.Second,
+		Mode:         ListenerModePoll,
+	}
+	if path := cfg.StatePath(); path != filepath.Join(dataDir, "mainnet.json") {
+		t.Fatalf("state path %s, expected a file named after the listener in the data dir", path)
+	}
+
+	listener, err := NewBeaconListener(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	l := listener.(*BeaconListener)
+	t.Cleanup(l.cancel)
+	if !l.send(make(chan Report, 1), testEthBlock(5, 4)) {
+		t.Fatal("listener stopped")
+	}
+
+	// a restarted listener continues after the last report
+	restarted, err := NewBeaconListener(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := restarted.(*BeaconListener)
+	t.Cleanup(r.cancel)
+	if r.lastSlot != 5 || r.lastRoot != testEthBlock(5, 4).BlockRoot {
+		t.Fatalf("restored slot %d and root %s, expected the last report", r.lastSlot, r.lastRoot)
+	}
+}
@@ -0,0 +1,153 @@
+package rollup
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sethvargo/go-envconfig"
+)
+
+// Report is a report about a block of some chain produced by a chain listener. The app wraps it in a tx,
+// signs it and sequences it.
+type Report struct {
+	ChainID     string
+	PayloadType PayloadType
+	Payload     Payload
+	// Finality is the finality level of the reported block.
+	Finality Finality
+}
+
+// ChainListener watches a chain and emits reports about its blocks.
+type ChainListener interface {
+	// ChainID is the chain the listener reports on.
+	ChainID() string
+	// Start starts listening in the background, sending reports to the given channel until Stop is called.
+	Start(reports chan<- Report) error
+	// Stop stops the listener and waits for it to exit.
+	Stop()
+}
+
+// ListenerConfig configures a chain listener. The settings of a listener are read from env vars prefixed with its
+// upper cased name, e.g. ETHEREUM_RPC for the listener named ethereum.
+type ListenerConfig struct {
+	Name string
+	// DataDir is the directory listeners persist their progress in, see StatePath. Nothing is persisted if it is
+	// empty.
+	DataDir string
+	// Kind selects the listener implementation, see RegisterChainListener. It defaults to the name.
+	Kind string `env:"KIND"`
+	// ChainID is the chain id reports are submitted for. It defaults to the name.
+	ChainID string `env:"CHAIN_ID"`
+	// Rpcs are the comma separated endpoints of the chain's nodes. Requests fail over to the next endpoint while
+	// one is down.
+	Rpcs         []string      `env:"RPC, required"`
+	PollInterval time.Duration `env:"POLL_INTERVAL, default=15s"`
+	// Finality is the finality level of the blocks the listener reports, see Finality.
+	Finality Finality `env:"FINALITY, default=head"`
+	// Mode is how the listener follows the chain. Not all kinds support all modes.
+	Mode ListenerMode `env:"MODE, default=poll"`
+	// MaxBackfill is the maximum number of missed slots or blocks a listener reports when it detects a gap.
+	MaxBackfill uint64 `env:"MAX_BACKFILL, default=64"`
+	// CrossCheck is the number of endpoints which have to return the same block for it to be reported. Blocks are
+	// reported as returned by a single endpoint if it is 0 or 1.
+	CrossCheck int `env:"CROSS_CHECK, default=0"`
+}
+
+// StatePath is the file the listener persists its progress in, or an empty path if there is no data dir.
+func (c *ListenerConfig) StatePath() string {
+	if c.DataDir == "" {
+		return ""
+	}
+	return filepath.Join(c.DataDir, c.Name+".json")
+}
+
+// ListenerMode is how a listener follows its chain.
+type ListenerMode string
+
+const (
+	// ListenerModePoll polls the node every poll interval.
+	ListenerModePoll ListenerMode = "poll"
+	// ListenerModeEvents subscribes to the node's events and falls back to polling while it can't.
+	ListenerModeEvents ListenerMode = "events"
+)
+
+// ListenerFactory creates a chain listener from its config.
+type ListenerFactory func(cfg ListenerConfig) (ChainListener, error)
+
+var (
+	listenerFactoriesLock sync.RWMutex
+	listenerFactories     = map[string]ListenerFactory{
+		EthereumChainID: NewBeaconListener,
+	}
+)
+
+// RegisterChainListener registers the factory of a listener kind, replacing any existing factory.
+func RegisterChainListener(kind string, factory ListenerFactory) {
+	listenerFactoriesLock.Lock()
+	defer listenerFactoriesLock.Unlock()
+	listenerFactories[kind] = factory
+}
+
+func GetListenerFactory(kind string) (ListenerFactory, bool) {
+	listenerFactoriesLock.RLock()
+	defer listenerFactoriesLock.RUnlock()
+	factory, ok := listenerFactories[kind]
+	return factory, ok
+}
+
+// LoadListenerConfig reads the config of the listener with the given name from the environment.
+func LoadListenerConfig(ctx context.Context, name string, dataDir string) (ListenerConfig, error) {
+	cfg := ListenerConfig{}
+	err := envconfig.ProcessWith(ctx, &envconfig.Config{
+		Target:   &cfg,
+		Lookuper: envconfig.PrefixLookuper(strings.ToUpper(name)+"_", envconfig.OsLookuper()),
+	})
+	if err != nil {
+		return ListenerConfig{}, fmt.Errorf("failed to read config of listener %s: %w", name, err)
+	}
+	cfg.Name = name
+	cfg.DataDir = dataDir
+	if cfg.Kind == "" {
+		cfg.Kind = name
+	}
+	if cfg.ChainID == "" {
+		cfg.ChainID = name
+	}
+	if err := validateChainID(cfg.ChainID); err != nil {
+		return ListenerConfig{}, fmt.Errorf("invalid config of listener %s: %w", name, err)
+	}
+	if cfg.Finality, err = ParseFinality(string(cfg.Finality)); err != nil {
+		return ListenerConfig{}, fmt.Errorf("invalid config of listener %s: %w", name, err)
+	}
+	return cfg, nil
+}
+
+// NewChainListener creates a listener of the configured kind.
+func NewChainListener(cfg ListenerConfig) (ChainListener, error) {
+	factory, ok := GetListenerFactory(cfg.Kind)
+	if !ok {
+		return nil, fmt.Errorf("unknown listener kind %s for listener %s", cfg.Kind, cfg.Name)
+	}
+	return factory(cfg)
+}
+
+// LoadChainListeners creates the listeners with the given names from their configs in the environment.
+func LoadChainListeners(ctx context.Context, names []string, dataDir string) ([]ChainListener, error) {
+	listeners := []ChainListener{}
+	for _, name := range names {
+		cfg, err := LoadListenerConfig(ctx, name, dataDir)
+		if err != nil {
+			return nil, err
+		}
+		listener, err := NewChainListener(cfg)
+		if err != nil {
+			return nil, err
+		}
+		listeners = append(listeners, listener)
+	}
+	return listeners, nil
+}
@@ -1,11 +1,23 @@
 package rollup
 
 type Config struct {
-	EthereumRpc  string `env:"ETHEREUM_RPC, default=http://localhost:8545"`
 	SequencerRpc string `env:"SEQUENCER_RPC, default=http://localhost:26657"`
 	ConductorRpc string `env:"CONDUCTOR_RPC, default=http://localhost:50051"`
 	RollupName   string `env:"ROLLUP_NAME, default=multichain-oracle-rollup"`
-	RollupId     string `env:"ROLLUP_ID, default=multichain-oracle-rollup"`
-	SeqPrivate   string `env:"SEQUENCER_PRIVATE, default="`
+	SeqPrivate   string `env:"SEQUENCER_PRIVATE, required"`
 	RESTApiPort  string `env:"RESTAPI_PORT, default=:8080"`
+	DataDir      string `env:"DATA_DIR, default=data"`
+	GenesisFile  string `env:"GENESIS_FILE, default=genesis.json"`
+
+	// ListenerDataDir is where chain listeners persist their progress. It is separate from DataDir, which is the
+	// pebble store's directory. Nothing is persisted if it is empty.
+	ListenerDataDir string `env:"LISTENER_DATA_DIR, default=listeners"`
+
+	// ReporterPrivate is the hex encoded ed25519 seed reports are signed with. The sequencer key is used if it is empty.
+	ReporterPrivate string `env:"REPORTER_PRIVATE, default="`
+	// TxAPIToken is the bearer token required to post governance txs to the REST API, which the node sequences with
+	// its sequencer key. Posting txs is disabled if it is empty.
+	TxAPIToken string `env:"TX_API_TOKEN, default="`
+	// ChainListeners are the names of the chain listeners to run, see LoadListenerConfig.
+	ChainListeners []string `env:"CHAIN_LISTENERS, default=ethereum"`
 }
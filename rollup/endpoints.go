@@ -0,0 +1,108 @@
+package rollup
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	// minEndpointBackoff and maxEndpointBackoff bound how long a failing endpoint is skipped.
+	minEndpointBackoff = time.Second
+	maxEndpointBackoff = 5 * time.Minute
+)
+
+// endpoint is an rpc endpoint of a chain along with its health.
+type endpoint struct {
+	url string
+	// name identifies the endpoint in logs without its path or query, which often contain api keys.
+	name string
+	// failures is the number of consecutive failed requests. The endpoint is skipped until downUntil after a failure.
+	failures  int
+	downUntil time.Time
+}
+
+func (e *endpoint) String() string {
+	return e.name
+}
+
+// endpointPool tracks the health of the rpc endpoints of a chain, so that requests fail over to another endpoint
+// while an endpoint is down. It isn't safe for concurrent use.
+type endpointPool struct {
+	endpoints []*endpoint
+	// preferred is the index of the endpoint which succeeded last, which is tried first.
+	preferred int
+}
+
+func newEndpointPool(urls []string) (*endpointPool, error) {
+	if len(urls) == 0 {
+		return nil, fmt.Errorf("no endpoints")
+	}
+	pool := &endpointPool{}
+	for i, rawURL := range urls {
+		rawURL = strings.TrimSuffix(strings.TrimSpace(rawURL), "/")
+		u, err := url.Parse(rawURL)
+		if err != nil || u.Host == "" {
+			return nil, fmt.Errorf("invalid endpoint %d", i)
+		}
+		pool.endpoints = append(pool.endpoints, &endpoint{
+			url:  rawURL,
+			name: fmt.Sprintf("%s://%s", u.Scheme, u.Host),
+		})
+	}
+	return pool, nil
+}
+
+// all returns all endpoints regardless of their health.
+func (p *endpointPool) all() []*endpoint {
+	return p.endpoints
+}
+
+// healthy returns the endpoints which aren't down, starting with the preferred endpoint. If all endpoints are down,
+// the one which comes back up first is returned, so that there is always an endpoint to try.
+func (p *endpointPool) healthy() []*endpoint {
+	now := time.Now()
+	healthy := []*endpoint{}
+	var next *endpoint
+	for i := range p.endpoints {
+		e := p.endpoints[(p.preferred+i)%len(p.endpoints)]
+		if !now.Before(e.downUntil) {
+			healthy = append(healthy, e)
+		} else if next == nil || e.downUntil.Before(next.downUntil) {
+			next = e
+		}
+	}
+	if len(healthy) == 0 {
+		healthy = append(healthy, next)
+	}
+	return healthy
+}
+
+// succeeded marks the endpoint as healthy and prefers it for the next requests.
+func (p *endpointPool) succeeded(e *endpoint) {
+	e.failures = 0
+	e.downUntil = time.Time{}
+	for i, other := range p.endpoints {
+		if other == e {
+			p.preferred = i
+		}
+	}
+}
+
+// failed marks the endpoint as down for a backoff which doubles with each consecutive failure.
+func (p *endpointPool) failed(e *endpoint) {
+	backoff := minEndpointBackoff << min(e.failures, 16)
+	e.failures++
+	e.downUntil = time.Now().Add(min(backoff, maxEndpointBackoff))
+}
+
+// stripURL removes the url from the error of an http request, since it may contain an api key.
+func stripURL(err error) error {
+	urlErr := &url.Error{}
+	if errors.As(err, &urlErr) {
+		return fmt.Errorf("%s: %w", urlErr.Op, urlErr.Err)
+	}
+	return err
+}
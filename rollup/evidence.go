@@ -0,0 +1,81 @@
+package rollup
+
+import (
+	"blockchain-oracle/merkle"
+	"encoding/json"
+	"fmt"
+)
+
+const evidencePrefix = "evidence/"
+
+// EvidenceKey is the state key of the evidence against a reporter for a slot or block height of a finality level
+// of a chain. Only justified and finalized reports are jailed for, see Evidence.
+func EvidenceKey(chainID string, finality Finality, reportHeight uint64, reporter string) string {
+	return fmt.Sprintf("%s%s/%s/%020d/%s", evidencePrefix, chainID, finality, reportHeight, reporter)
+}
+
+// Evidence records a reporter signing two different justified or finalized reports for the same slot or block
+// height of a chain.
+type Evidence struct {
+	ChainID      string   `json:"chain_id"`
+	Finality     Finality `json:"finality,omitempty"`
+	ReportHeight uint64   `json:"report_height"`
+	// Reporter is the hex encoded public key of the reporter, which was jailed.
+	Reporter string `json:"reporter"`
+	// RollupHeight is the height of the block in which the equivocation was detected.
+	RollupHeight uint32 `json:"rollup_height"`
+	// PreviousDigest and PreviousData are the report the reporter submitted first, which is either pending
+	// or part of the canonical record at ReportHeight.
+	PreviousDigest merkle.Hash     `json:"previous_digest"`
+	PreviousData   json.RawMessage `json:"previous_data"`
+	// ConflictingTx is the signed tx with the conflicting report.
+	ConflictingTx *Transaction `json:"conflicting_tx"`
+}
+
+// EquivocationError is returned for a report which conflicts with an earlier report of the same reporter.
+// Unlike other tx errors, the state changes of the tx, which record the evidence and jail the reporter, are kept.
+type EquivocationError struct {
+	Evidence Evidence
+}
+
+func (e *EquivocationError) Error() string {
+	return fmt.Sprintf("reporter %s equivocated on %s height %d and was jailed",
+		e.Evidence.Reporter, e.Evidence.ChainID, e.Evidence.ReportHeight)
+}
+
+// GetEvidence returns all evidence ordered by chain, finality level and height.
+func GetEvidence(state *State) ([]Evidence, error) {
+	evidence := []Evidence{}
+	for _, key := range state.KeysWithPrefix(evidencePrefix) {
+		value, _ := state.Get(key)
+		e := Evidence{}
+		if err := json.Unmarshal(value, &e); err != nil {
+			return nil, err
+		}
+		evidence = append(evidence, e)
+	}
+	return evidence, nil
+}
+
+// jailForEquivocation records the evidence and jails the reporter, so that its reports are rejected until
+// governance unjails it.
+func jailForEquivocation(state StateReadWriter, evidence Evidence) error {
+	value, err := json.Marshal(evidence)
+	if err != nil {
+		return err
+	}
+	state.Set(EvidenceKey(evidence.ChainID, evidence.Finality, evidence.ReportHeight, evidence.Reporter), value)
+
+	reporter, err := GetReporter(state, evidence.Reporter)
+	if err != nil {
+		return err
+	}
+	if reporter == nil {
+		return fmt.Errorf("reporter %s is not in the reporter set", evidence.Reporter)
+	}
+	reporter.Jailed = true
+	if err := setReporter(state, *reporter); err != nil {
+		return err
+	}
+	return &EquivocationError{Evidence: evidence}
+}
@@ -1,41 +1,40 @@
 package rollup
 
 import (
-	"bytes"
 	"context"
 	"encoding/hex"
-	"encoding/json"
 	"errors"
-	"time"
 
 	log "github.com/sirupsen/logrus"
 
 	astriaGrpc "buf.build/gen/go/astria/execution-apis/grpc/go/astria/execution/v1alpha2/executionv1alpha2grpc"
 	astriaPb "buf.build/gen/go/astria/execution-apis/protocolbuffers/go/astria/execution/v1alpha2"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 // ExecutionServiceServerV1Alpha2 is a server that implements the ExecutionServiceServer interface.
 type ExecutionServiceServerV1Alpha2 struct {
 	astriaGrpc.UnimplementedExecutionServiceServer
-	rollup   *Rollup
-	rollupID []byte
+	rollup  *Rollup
+	genesis *Genesis
 }
 
 // NewExecutionServiceServerV1Alpha2 creates a new ExecutionServiceServerV1Alpha2.
-func NewExecutionServiceServerV1Alpha2(rollup *Rollup, rollupID []byte) *ExecutionServiceServerV1Alpha2 {
+func NewExecutionServiceServerV1Alpha2(rollup *Rollup, genesis *Genesis) *ExecutionServiceServerV1Alpha2 {
 	return &ExecutionServiceServerV1Alpha2{
-		rollup:   rollup,
-		rollupID: rollupID,
+		rollup:  rollup,
+		genesis: genesis,
 	}
 }
 
 func (s *ExecutionServiceServerV1Alpha2) GetGenesisInfo(ctx context.Context, req *astriaPb.GetGenesisInfoRequest) (*astriaPb.GenesisInfo, error) {
 	log.Debug("GetGenesisInfo called")
 	res := &astriaPb.GenesisInfo{
-		RollupId:                    s.rollupID,
-		SequencerGenesisBlockHeight: uint32(1),
-		CelestiaBaseBlockHeight:     uint32(1),
-		CelestiaBlockVariance:       uint32(1),
+		RollupId:                    s.genesis.RollupID(),
+		SequencerGenesisBlockHeight: s.genesis.SequencerGenesisBlockHeight,
+		CelestiaBaseBlockHeight:     s.genesis.CelestiaBaseBlockHeight,
+		CelestiaBlockVariance:       s.genesis.CelestiaBlockVariance,
 	}
 	log.WithFields(log.Fields{
 		"rollupId": hex.EncodeToString(res.RollupId),
@@ -43,30 +42,49 @@ func (s *ExecutionServiceServerV1Alpha2) GetGenesisInfo(ctx context.Context, req
 	return res, nil
 }
 
+// getBlockByIdentifier looks up a block by number or hash. It returns gRPC status errors.
+func getBlockByIdentifier(snapshot *Snapshot, id *astriaPb.BlockIdentifier) (*Block, error) {
+	var block *Block
+	var err error
+	switch id.GetIdentifier().(type) {
+	case *astriaPb.BlockIdentifier_BlockNumber:
+		block, err = snapshot.GetBlock(id.GetBlockNumber())
+	case *astriaPb.BlockIdentifier_BlockHash:
+		if len(id.GetBlockHash()) != 32 {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid block hash length %d", len(id.GetBlockHash()))
+		}
+		block, err = snapshot.GetBlockByHash([32]byte(id.GetBlockHash()))
+	default:
+		return nil, status.Errorf(codes.InvalidArgument, "invalid identifier: %v", id)
+	}
+	if errors.Is(err, ErrBlockNotFound) {
+		return nil, status.Errorf(codes.NotFound, "block not found: %v", id)
+	}
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return block, nil
+}
+
 // GetBlock retrieves a block by its identifier.
 func (s *ExecutionServiceServerV1Alpha2) GetBlock(ctx context.Context, req *astriaPb.GetBlockRequest) (*astriaPb.Block, error) {
 	log.WithField(
 		"identifier", req.Identifier,
 	).Debug("GetBlock called")
-	switch req.Identifier.Identifier.(type) {
-	case *astriaPb.BlockIdentifier_BlockNumber:
-		block, err := s.rollup.GetSingleBlock(req.Identifier.GetBlockNumber())
-		if err != nil {
-			return nil, err
-		}
-		blockPb, err := block.ToPb()
-		if err != nil {
-			return nil, err
-		}
-
-		log.WithField(
-			"blockHash", hex.EncodeToString(block.Hash[:]),
-		).Debugf("GetBlock completed with response: %v\n", block)
-		return blockPb, nil
-	default:
-		log.Debugf("GetBlock completed with error: invalid identifier: %v\n", req.Identifier)
-		return nil, errors.New("invalid identifier")
+	block, err := getBlockByIdentifier(s.rollup.Snapshot(), req.Identifier)
+	if err != nil {
+		log.Debugf("GetBlock completed with error: %s\n", err)
+		return nil, err
+	}
+	blockPb, err := block.ToPb()
+	if err != nil {
+		return nil, err
 	}
+
+	log.WithField(
+		"blockHash", hex.EncodeToString(block.Hash[:]),
+	).Debugf("GetBlock completed with response: %v\n", block)
+	return blockPb, nil
 }
 
 // BatchGetBlocks retrieves multiple blocks by their identifiers.
@@ -77,20 +95,18 @@ func (s *ExecutionServiceServerV1Alpha2) BatchGetBlocks(ctx context.Context, req
 	res := &astriaPb.BatchGetBlocksResponse{
 		Blocks: []*astriaPb.Block{},
 	}
+	snapshot := s.rollup.Snapshot()
 	for _, id := range req.Identifiers {
-		switch id.Identifier.(type) {
-		case *astriaPb.BlockIdentifier_BlockNumber:
-			height := id.GetBlockNumber()
-			block, err := s.rollup.GetSingleBlock(height)
-			if err != nil {
-				return nil, err
-			}
-			blockPb, err := block.ToPb()
-			if err != nil {
-				return nil, err
-			}
-			res.Blocks = append(res.Blocks, blockPb)
+		block, err := getBlockByIdentifier(snapshot, id)
+		if err != nil {
+			log.Debugf("BatchGetBlocks completed with error: %s\n", err)
+			return nil, err
+		}
+		blockPb, err := block.ToPb()
+		if err != nil {
+			return nil, err
 		}
+		res.Blocks = append(res.Blocks, blockPb)
 	}
 
 	log.Debugf("BatchGetBlocks completed with response: %v\n", res)
@@ -100,55 +116,56 @@ func (s *ExecutionServiceServerV1Alpha2) BatchGetBlocks(ctx context.Context, req
 // ExecuteBlock executes a block and adds it to the blockchain.
 func (s *ExecutionServiceServerV1Alpha2) ExecuteBlock(ctx context.Context, req *astriaPb.ExecuteBlockRequest) (*astriaPb.Block, error) {
 	log.WithField("prevBlockHash", hex.EncodeToString(req.PrevBlockHash)).Debugf("ExecuteBlock called")
-	// check if the prev block hash matches the current latest block
-	if !bytes.Equal(req.PrevBlockHash, s.rollup.GetLatestBlock().Hash[:]) {
+	log.WithField("txs", len(req.Transactions)).Debugf("executing transactions")
+	block, err := s.rollup.ExecuteBlock(req.PrevBlockHash, req.Transactions, req.Timestamp.AsTime())
+	if errors.Is(err, ErrBlockNotFound) {
 		return nil, errors.New("invalid prev block hash")
 	}
-	txs := []Transaction{}
-	log.WithField("txs", len(req.Transactions)).Debugf("unmarshalling transactions")
-	for _, txBytes := range req.Transactions {
-		tx := &Transaction{}
-		if err := json.Unmarshal(txBytes, tx); err != nil {
-			return nil, errors.New("failed to unmarshal transaction")
+	if err != nil {
+		return nil, err
+	}
+
+	for _, receipt := range block.Receipts {
+		if receipt.Status != ReceiptAccepted {
+			log.WithFields(log.Fields{
+				"height":  block.Height,
+				"txIndex": receipt.TxIndex,
+				"txHash":  receipt.TxHash,
+				"status":  receipt.Status,
+				"reason":  receipt.Reason,
+			}).Warn("rejected transaction")
 		}
-		txs = append(txs, *tx)
-		log.WithFields(
-			log.Fields{
-				"Block Hash":     tx.FinalizedEthBlockData.BlockHash,
-				"Parent Hash":    tx.FinalizedEthBlockData.ParentRoot,
-				"State Root":     tx.FinalizedEthBlockData.StateRoot,
-				"Slot":           tx.FinalizedEthBlockData.Slot,
-				"Proposer Index": tx.FinalizedEthBlockData.ProposerIndex,
-			},
-		).Debug("unmarshalled transaction")
-	}
-	block := Block{
-		ParentHash: [32]byte{},
-		Hash:       [32]byte{},
-		Height:     0,
-		Timestamp:  time.Now(),
-		Txs:        []Transaction{},
-	}
-	block = NewBlock(req.PrevBlockHash, uint32(len(s.rollup.Blocks)), txs, req.Timestamp.AsTime())
-	s.rollup.Blocks = append(s.rollup.Blocks, block)
+	}
 
 	blockPb, err := block.ToPb()
 	if err != nil {
 		return nil, errors.New("failed to convert block to protobuf")
 	}
 
-	log.WithField("blockHash", hex.EncodeToString(block.Hash[:])).Debugf("ExecuteBlock completed")
+	log.WithFields(log.Fields{
+		"blockHash": hex.EncodeToString(block.Hash[:]),
+		"stateRoot": hex.EncodeToString(block.StateRoot[:]),
+	}).Debugf("ExecuteBlock completed")
 	return blockPb, nil
 }
 
 // GetCommitmentState retrieves the current commitment state of the blockchain.
 func (s *ExecutionServiceServerV1Alpha2) GetCommitmentState(ctx context.Context, req *astriaPb.GetCommitmentStateRequest) (*astriaPb.CommitmentState, error) {
 	log.Debug("GetCommitmentState called")
-	soft, err := s.rollup.GetSoftBlock().ToPb()
+	snapshot := s.rollup.Snapshot()
+	softBlock, err := snapshot.GetSoftBlock()
+	if err != nil {
+		return nil, err
+	}
+	soft, err := softBlock.ToPb()
 	if err != nil {
 		return nil, err
 	}
-	firm, err := s.rollup.GetFirmBlock().ToPb()
+	firmBlock, err := snapshot.GetFirmBlock()
+	if err != nil {
+		return nil, err
+	}
+	firm, err := firmBlock.ToPb()
 	if err != nil {
 		return nil, err
 	}
@@ -180,34 +197,17 @@ func (s *ExecutionServiceServerV1Alpha2) UpdateCommitmentState(ctx context.Conte
 	softHeight := req.CommitmentState.Soft.Number
 	firmHeight := req.CommitmentState.Firm.Number
 
-	// get the actual soft and firm blocks
-	firmBlock, err := s.rollup.GetSingleBlock(firmHeight)
+	// update the commitment state, this checks the firm block hash against the actual firm block
+	snapshot, err := s.rollup.UpdateCommitment(softHeight, firmHeight, req.CommitmentState.Firm.Hash)
 	if err != nil {
+		log.Debugf("UpdateCommitmentState completed with error: %s", err)
 		return nil, err
 	}
-	//softBlock, err := s.rollup.GetSingleBlock(softHeight)
-	//if err != nil {
-	//	return nil, err
-	//}
-
-	if !bytes.Equal(firmBlock.Hash[:], req.CommitmentState.Firm.Hash) {
-		log.Debug("UpdateCommitmentState completed with error: firm block hash mismatch")
-		return nil, errors.New("firm block hash mismatch")
-	}
-	// compare actual blocks to commit state
-	//if !bytes.Equal(softBlock.Hash[:], req.CommitmentState.Soft.Hash) {
-	//	log.Debug("UpdateCommitmentState completed with error: soft block hash mismatch")
-	//	return nil, errors.New("soft block hash mismatch")
-	//}
-
-	// update the commitment state
-	s.rollup.soft = softHeight
-	s.rollup.firm = firmHeight
 
 	log.WithFields(
 		log.Fields{
-			"soft": s.rollup.soft,
-			"firm": s.rollup.firm,
+			"soft": snapshot.Soft(),
+			"firm": snapshot.Firm(),
 		},
 	).Debugf("UpdateCommitmentState completed")
 	return req.CommitmentState, nil
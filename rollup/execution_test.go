@@ -0,0 +1,66 @@
+package rollup
+
+import (
+	"bytes"
+	"testing"
+
+	astriaPb "buf.build/gen/go/astria/execution-apis/protocolbuffers/go/astria/execution/v1alpha2"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestGetBlockByIdentifier(t *testing.T) {
+	reporter := testReporter(1)
+	r, err := NewRollup(NewMemoryStore(), testGenesis(1, reporter), make(chan Block, 100))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for slot := uint64(1); slot <= 3; slot++ {
+		executeTestBlock(t, r, testEthTx(t, reporter, FinalityHead, testEthBlock(slot, slot-1)))
+	}
+	// block 1 is only in the store, blocks 2 and 3 are unfinalized
+	firmTestBlock(t, r, 2)
+	snapshot := r.Snapshot()
+	blocks := []*Block{}
+	for height := uint32(0); height < snapshot.Height(); height++ {
+		block, err := snapshot.GetBlock(height)
+		if err != nil {
+			t.Fatal(err)
+		}
+		blocks = append(blocks, block)
+	}
+	byNumber := func(number uint32) *astriaPb.BlockIdentifier {
+		return &astriaPb.BlockIdentifier{Identifier: &astriaPb.BlockIdentifier_BlockNumber{BlockNumber: number}}
+	}
+	byHash := func(hash []byte) *astriaPb.BlockIdentifier {
+		return &astriaPb.BlockIdentifier{Identifier: &astriaPb.BlockIdentifier_BlockHash{BlockHash: hash}}
+	}
+
+	for _, c := range []struct {
+		name   string
+		id     *astriaPb.BlockIdentifier
+		code   codes.Code
+		height uint32
+	}{
+		{"genesis by number", byNumber(0), codes.OK, 0},
+		{"unfinalized block by number", byNumber(3), codes.OK, 3},
+		{"number above the latest block", byNumber(4), codes.NotFound, 0},
+		{"finalized block by hash", byHash(blocks[1].Hash[:]), codes.OK, 1},
+		{"firm block by hash", byHash(blocks[2].Hash[:]), codes.OK, 2},
+		{"latest block by hash", byHash(blocks[3].Hash[:]), codes.OK, 3},
+		{"unknown hash", byHash(bytes.Repeat([]byte{1}, 32)), codes.NotFound, 0},
+		{"short hash", byHash(blocks[1].Hash[:31]), codes.InvalidArgument, 0},
+		{"long hash", byHash(append(blocks[1].Hash[:], 0)), codes.InvalidArgument, 0},
+		{"empty identifier", &astriaPb.BlockIdentifier{}, codes.InvalidArgument, 0},
+		{"missing identifier", nil, codes.InvalidArgument, 0},
+	} {
+		block, err := getBlockByIdentifier(snapshot, c.id)
+		if code := status.Code(err); code != c.code {
+			t.Errorf("%s: got code %s (%v), expected %s", c.name, code, err, c.code)
+			continue
+		}
+		if c.code == codes.OK && (block.Height != c.height || block.Hash != blocks[c.height].Hash) {
+			t.Errorf("%s: got block %d, expected block %d", c.name, block.Height, c.height)
+		}
+	}
+}
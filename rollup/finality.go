@@ -0,0 +1,63 @@
+package rollup
+
+import (
+	"blockchain-oracle/stateproof"
+	"fmt"
+	"strings"
+)
+
+// Finality is how final the block a report is about is on its chain. Each finality level of a chain is tracked
+// separately: reports are only tallied and validated against reports of the same level, and the state keeps the
+// latest record of each level. An empty finality is the head, which is what reports were before finality levels.
+type Finality string
+
+const (
+	// FinalityHead is the head of the chain, which can still be reorged.
+	FinalityHead Finality = "head"
+	// FinalityJustified is the latest justified checkpoint of an ethereum beacon chain.
+	FinalityJustified Finality = "justified"
+	// FinalityFinalized is the latest finalized block.
+	FinalityFinalized Finality = "finalized"
+)
+
+// ParseFinality parses a finality level, an empty string is the head.
+func ParseFinality(s string) (Finality, error) {
+	finality := Finality(s)
+	if err := finality.validate(); err != nil {
+		return "", err
+	}
+	return finality.orHead(), nil
+}
+
+func (f Finality) validate() error {
+	switch f {
+	case "", FinalityHead, FinalityJustified, FinalityFinalized:
+		return nil
+	}
+	return fmt.Errorf("unknown finality %s", f)
+}
+
+// orHead returns the finality with an empty finality replaced by FinalityHead.
+func (f Finality) orHead() Finality {
+	if f == "" {
+		return FinalityHead
+	}
+	return f
+}
+
+// chainKeyPrefix is the prefix of the state keys of a finality level of a chain. Head keys are directly under the
+// chain, so that the keys of chains which were reported before finality levels don't change.
+func chainKeyPrefix(chainID string, finality Finality) string {
+	return stateproof.ChainKeyPrefix(chainID, string(finality.orHead()))
+}
+
+// validateChainID rejects chain ids which could make the state keys of a chain collide with another chain's.
+func validateChainID(chainID string) error {
+	if chainID == "" {
+		return fmt.Errorf("missing chain id")
+	}
+	if strings.Contains(chainID, "/") {
+		return fmt.Errorf("chain id %s contains a /", chainID)
+	}
+	return nil
+}
@@ -0,0 +1,172 @@
+package rollup
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+)
+
+// Genesis defines the genesis block and state of the rollup. It is loaded from a JSON file so that
+// every node computes the same genesis block.
+type Genesis struct {
+	Timestamp  time.Time `json:"timestamp"`
+	RollupName string    `json:"rollup_name"`
+	// heights reported to the conductor in GetGenesisInfo
+	SequencerGenesisBlockHeight uint32 `json:"sequencer_genesis_block_height"`
+	CelestiaBaseBlockHeight     uint32 `json:"celestia_base_block_height"`
+	CelestiaBlockVariance       uint32 `json:"celestia_block_variance"`
+	// Reporters are the hex encoded ed25519 public keys of the initial reporter set.
+	Reporters []string `json:"reporters"`
+	// QuorumThreshold is the number of reporters which have to submit matching reports before a report becomes
+	// canonical. It defaults to 1.
+	QuorumThreshold uint32 `json:"quorum_threshold,omitempty"`
+	// Admins are the hex encoded ed25519 public keys of the initial admins, which authorize governance txs.
+	// Governance is disabled if there are none.
+	Admins []string `json:"admins,omitempty"`
+	// AdminThreshold is the number of admins which have to sign a governance tx. It defaults to 1.
+	AdminThreshold uint32 `json:"admin_threshold,omitempty"`
+	// Checkpoints are the initial latest records of each chain.
+	Checkpoints []ChainRecord `json:"checkpoints"`
+}
+
+// DefaultGenesis returns the genesis used when no genesis file is configured.
+func DefaultGenesis(rollupName string) *Genesis {
+	return &Genesis{
+		Timestamp:                   time.Unix(0, 0).UTC(),
+		RollupName:                  rollupName,
+		SequencerGenesisBlockHeight: 1,
+		CelestiaBaseBlockHeight:     1,
+		CelestiaBlockVariance:       1,
+		Reporters:                   []string{},
+		Checkpoints:                 []ChainRecord{},
+	}
+}
+
+// LoadGenesis reads and validates the genesis file at the given path.
+func LoadGenesis(path string) (*Genesis, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read genesis file: %w", err)
+	}
+	genesis := &Genesis{}
+	if err := json.Unmarshal(data, genesis); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal genesis file: %w", err)
+	}
+	if err := genesis.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid genesis file: %w", err)
+	}
+	return genesis, nil
+}
+
+func (g *Genesis) Validate() error {
+	if g.Timestamp.IsZero() {
+		return errors.New("missing timestamp")
+	}
+	if g.RollupName == "" {
+		return errors.New("missing rollup name")
+	}
+	for _, reporter := range g.Reporters {
+		if _, err := decodePublicKey(reporter); err != nil {
+			return fmt.Errorf("invalid reporter %s: %w", reporter, err)
+		}
+	}
+	if int(g.QuorumThreshold) > len(g.Reporters) {
+		return fmt.Errorf("quorum threshold %d is larger than the reporter set", g.QuorumThreshold)
+	}
+	if len(g.Admins) > 0 {
+		admins := g.adminSet()
+		if err := admins.validate(); err != nil {
+			return err
+		}
+	}
+	for _, checkpoint := range g.Checkpoints {
+		if checkpoint.ChainID == "" {
+			return errors.New("checkpoint is missing a chain id")
+		}
+		if err := validateChainID(checkpoint.ChainID); err != nil {
+			return err
+		}
+		if err := checkpoint.Finality.validate(); err != nil {
+			return fmt.Errorf("invalid checkpoint for chain %s: %w", checkpoint.ChainID, err)
+		}
+		if _, err := checkpoint.DecodeData(); err != nil {
+			return fmt.Errorf("invalid checkpoint for chain %s: %w", checkpoint.ChainID, err)
+		}
+	}
+	return nil
+}
+
+// RollupID is the id of the rollup on the shared sequencer.
+func (g *Genesis) RollupID() []byte {
+	rollupID := sha256.Sum256([]byte(g.RollupName))
+	return rollupID[:]
+}
+
+// State returns the state at the genesis block.
+func (g *Genesis) State() (*State, error) {
+	state := NewState()
+	for _, reporter := range g.Reporters {
+		publicKey, err := decodePublicKey(reporter)
+		if err != nil {
+			return nil, err
+		}
+		if err := setReporter(state, Reporter{PublicKey: hex.EncodeToString(publicKey)}); err != nil {
+			return nil, err
+		}
+	}
+	if g.QuorumThreshold > 0 {
+		if err := setQuorumThreshold(state, g.QuorumThreshold); err != nil {
+			return nil, err
+		}
+	}
+	if len(g.Admins) > 0 {
+		admins := g.adminSet()
+		if err := admins.validate(); err != nil {
+			return nil, err
+		}
+		if err := setAdminSet(state, admins); err != nil {
+			return nil, err
+		}
+	}
+	for _, checkpoint := range g.Checkpoints {
+		checkpoint.RollupHeight = 0
+		if err := setChainRecord(state, checkpoint); err != nil {
+			return nil, err
+		}
+	}
+	return state, nil
+}
+
+// adminSet returns a copy of the initial admin set, which can be normalized without modifying the genesis.
+func (g *Genesis) adminSet() AdminSet {
+	threshold := g.AdminThreshold
+	if threshold == 0 {
+		threshold = 1
+	}
+	return AdminSet{Admins: append([]string{}, g.Admins...), Threshold: threshold}
+}
+
+// Block returns the genesis block. It has no txs, the genesis checkpoints and reporters are part of its state.
+func (g *Genesis) Block() (Block, error) {
+	state, err := g.State()
+	if err != nil {
+		return Block{}, err
+	}
+	return NewBlock(make([]byte, 32), 0, [][]byte{}, []Receipt{}, state.Root(), g.Timestamp), nil
+}
+
+func decodePublicKey(publicKey string) (ed25519.PublicKey, error) {
+	bs, err := hex.DecodeString(publicKey)
+	if err != nil {
+		return nil, err
+	}
+	if len(bs) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("invalid public key length %d", len(bs))
+	}
+	return ed25519.PublicKey(bs), nil
+}
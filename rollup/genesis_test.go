@@ -0,0 +1,144 @@
+package rollup
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// testGenesisFile returns a genesis with reporters, admins and a checkpoint.
+func testGenesisFile(t *testing.T) *Genesis {
+	t.Helper()
+	genesis := testGenesis(2, testReporter(1), testReporter(2), testReporter(3))
+	genesis.Timestamp = time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	genesis.Admins = []string{testPublicKey(testReporter(4)), testPublicKey(testReporter(5))}
+	genesis.AdminThreshold = 2
+	data, err := json.Marshal(testEthBlock(64, 63))
+	if err != nil {
+		t.Fatal(err)
+	}
+	genesis.Checkpoints = []ChainRecord{{
+		ChainID:      EthereumChainID,
+		ReportHeight: 64,
+		PayloadType:  PayloadTypeEthBlock,
+		Finality:     FinalityFinalized,
+		Data:         data,
+	}}
+	return genesis
+}
+
+// writeGenesisFile writes the genesis to a file with the given encoding and returns its path.
+func writeGenesisFile(t *testing.T, genesis any, indent bool) string {
+	t.Helper()
+	var data []byte
+	var err error
+	if indent {
+		data, err = json.MarshalIndent(genesis, "", "    ")
+	} else {
+		data, err = json.Marshal(genesis)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "genesis.json")
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadGenesisIsDeterministic(t *testing.T) {
+	genesis := testGenesisFile(t)
+	// the formatting of the file doesn't matter, in particular of the checkpoint data which is stored as is
+	compact, indented := writeGenesisFile(t, genesis, false), writeGenesisFile(t, genesis, true)
+	for _, paths := range [][]string{
+		{"../genesis.json", "../genesis.json"},
+		{compact, compact, indented},
+	} {
+		var roots []string
+		for _, path := range paths {
+			loaded, err := LoadGenesis(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			block, err := loaded.Block()
+			if err != nil {
+				t.Fatal(err)
+			}
+			roots = append(roots, fmt.Sprintf("%x %x", block.StateRoot, block.Hash))
+		}
+		for _, root := range roots[1:] {
+			if root != roots[0] {
+				t.Fatalf("loading %v gave the state roots and hashes %v", paths, roots)
+			}
+		}
+	}
+
+	// the loaded genesis state has what the file defines
+	loaded, err := LoadGenesis(writeGenesisFile(t, genesis, true))
+	if err != nil {
+		t.Fatal(err)
+	}
+	state, err := loaded.State()
+	if err != nil {
+		t.Fatal(err)
+	}
+	record, err := GetChainRecord(state, EthereumChainID, FinalityFinalized)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if record == nil || record.ReportHeight != 64 {
+		t.Fatalf("checkpoint %+v, expected slot 64", record)
+	}
+	reporter, err := GetReporter(state, testPublicKey(testReporter(3)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reporter == nil {
+		t.Fatal("reporter from the genesis file is missing")
+	}
+}
+
+func TestLoadGenesisRejectsInvalidGenesis(t *testing.T) {
+	for _, c := range []struct {
+		name   string
+		modify func(genesis *Genesis)
+		err    string
+	}{
+		{"missing timestamp", func(g *Genesis) { g.Timestamp = time.Time{} }, "missing timestamp"},
+		{"missing rollup name", func(g *Genesis) { g.RollupName = "" }, "missing rollup name"},
+		{"malformed reporter", func(g *Genesis) { g.Reporters[1] = "zz" }, "invalid reporter"},
+		{"short reporter", func(g *Genesis) { g.Reporters[1] = g.Reporters[1][:62] }, "invalid public key length"},
+		{"quorum above the reporter set", func(g *Genesis) { g.QuorumThreshold = 4 }, "larger than the reporter set"},
+		{"malformed admin", func(g *Genesis) { g.Admins[0] = "zz" }, "invalid admin"},
+		{"duplicate admin", func(g *Genesis) { g.Admins[1] = g.Admins[0] }, "duplicate admin"},
+		{"admin threshold above the admin set", func(g *Genesis) { g.AdminThreshold = 3 }, "admin threshold 3"},
+		{"checkpoint without chain id", func(g *Genesis) { g.Checkpoints[0].ChainID = "" }, "missing a chain id"},
+		{"checkpoint with a / in its chain id", func(g *Genesis) { g.Checkpoints[0].ChainID = "eth/1" }, "contains a /"},
+		{"checkpoint with unknown finality", func(g *Genesis) { g.Checkpoints[0].Finality = "safe" }, "unknown finality"},
+		{"checkpoint with unknown payload type", func(g *Genesis) { g.Checkpoints[0].PayloadType = "foo" }, "unknown payload type"},
+		{"checkpoint with malformed data", func(g *Genesis) { g.Checkpoints[0].Data = json.RawMessage(`"foo"`) }, "invalid checkpoint"},
+	} {
+		genesis := testGenesisFile(t)
+		c.modify(genesis)
+		_, err := LoadGenesis(writeGenesisFile(t, genesis, false))
+		if err == nil || !strings.Contains(err.Error(), "invalid genesis file") || !strings.Contains(err.Error(), c.err) {
+			t.Errorf("%s: got error %v, expected %q", c.name, err, c.err)
+		}
+	}
+
+	path := filepath.Join(t.TempDir(), "genesis.json")
+	if _, err := LoadGenesis(path); err == nil || !strings.Contains(err.Error(), "failed to read genesis file") {
+		t.Errorf("missing file: got error %v", err)
+	}
+	if err := os.WriteFile(path, []byte(`{"timestamp":`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadGenesis(path); err == nil || !strings.Contains(err.Error(), "failed to unmarshal genesis file") {
+		t.Errorf("malformed file: got error %v", err)
+	}
+}
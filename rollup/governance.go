@@ -0,0 +1,360 @@
+package rollup
+
+import (
+	"crypto/ed25519"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+)
+
+const (
+	// GovernanceChainID is the reserved chain id of governance txs. Reports for it are rejected.
+	GovernanceChainID                 = "governance"
+	PayloadTypeGovernance PayloadType = "governance"
+)
+
+const (
+	// AdminsKey is the state key of the admin set, which authorizes governance txs.
+	AdminsKey = "params/admins"
+	// GovernanceNonceKey is the state key of the nonce the next governance tx has to use.
+	GovernanceNonceKey = "params/governance_nonce"
+)
+
+// AdminSet are the admins which authorize governance txs. A governance tx needs the signatures of at least
+// Threshold distinct admins.
+type AdminSet struct {
+	// Admins are the hex encoded ed25519 public keys of the admins.
+	Admins    []string `json:"admins"`
+	Threshold uint32   `json:"threshold"`
+}
+
+func (a *AdminSet) validate() error {
+	if len(a.Admins) == 0 {
+		return errors.New("admin set is empty")
+	}
+	seen := map[string]bool{}
+	for i, admin := range a.Admins {
+		publicKey, err := decodePublicKey(admin)
+		if err != nil {
+			return fmt.Errorf("invalid admin %s: %w", admin, err)
+		}
+		a.Admins[i] = hex.EncodeToString(publicKey)
+		if seen[a.Admins[i]] {
+			return fmt.Errorf("duplicate admin %s", admin)
+		}
+		seen[a.Admins[i]] = true
+	}
+	if a.Threshold == 0 || int(a.Threshold) > len(a.Admins) {
+		return fmt.Errorf("admin threshold %d is not between 1 and the number of admins", a.Threshold)
+	}
+	return nil
+}
+
+// GetAdminSet returns the admin set, or nil if governance is disabled.
+func GetAdminSet(state StateReadWriter) (*AdminSet, error) {
+	value, ok := state.Get(AdminsKey)
+	if !ok {
+		return nil, nil
+	}
+	admins := &AdminSet{}
+	if err := json.Unmarshal(value, admins); err != nil {
+		return nil, err
+	}
+	return admins, nil
+}
+
+func setAdminSet(state StateReadWriter, admins AdminSet) error {
+	value, err := json.Marshal(admins)
+	if err != nil {
+		return err
+	}
+	state.Set(AdminsKey, value)
+	return nil
+}
+
+func getGovernanceNonce(state StateReadWriter) (uint64, error) {
+	value, ok := state.Get(GovernanceNonceKey)
+	if !ok {
+		return 0, nil
+	}
+	var nonce uint64
+	if err := json.Unmarshal(value, &nonce); err != nil {
+		return 0, err
+	}
+	return nonce, nil
+}
+
+func setGovernanceNonce(state StateReadWriter, nonce uint64) error {
+	value, err := json.Marshal(nonce)
+	if err != nil {
+		return err
+	}
+	state.Set(GovernanceNonceKey, value)
+	return nil
+}
+
+// ChainConfigKey is the state key of the settings of a chain.
+func ChainConfigKey(chainID string) string {
+	return fmt.Sprintf("chain/%s/config", chainID)
+}
+
+// ChainConfig are the per chain settings changed by governance.
+type ChainConfig struct {
+	// Disabled chains don't accept reports.
+	Disabled bool `json:"disabled"`
+	// QuorumThreshold overrides the global quorum threshold for the chain if it is set.
+	QuorumThreshold uint32 `json:"quorum_threshold,omitempty"`
+}
+
+// GetChainConfig returns the settings of a chain. Chains without settings are enabled and use the global quorum
+// threshold.
+func GetChainConfig(state StateReadWriter, chainID string) (*ChainConfig, error) {
+	value, ok := state.Get(ChainConfigKey(chainID))
+	if !ok {
+		return &ChainConfig{}, nil
+	}
+	config := &ChainConfig{}
+	if err := json.Unmarshal(value, config); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+func setChainConfig(state StateReadWriter, chainID string, config *ChainConfig) error {
+	if *config == (ChainConfig{}) {
+		state.Delete(ChainConfigKey(chainID))
+		return nil
+	}
+	value, err := json.Marshal(config)
+	if err != nil {
+		return err
+	}
+	state.Set(ChainConfigKey(chainID), value)
+	return nil
+}
+
+type GovernanceActionType string
+
+const (
+	ActionAddReporter        GovernanceActionType = "add_reporter"
+	ActionRemoveReporter     GovernanceActionType = "remove_reporter"
+	ActionUnjailReporter     GovernanceActionType = "unjail_reporter"
+	ActionSetQuorumThreshold GovernanceActionType = "set_quorum_threshold"
+	ActionEnableChain        GovernanceActionType = "enable_chain"
+	ActionDisableChain       GovernanceActionType = "disable_chain"
+	ActionSetAdmins          GovernanceActionType = "set_admins"
+)
+
+// GovernanceAction is the payload of a governance tx.
+type GovernanceAction struct {
+	// Nonce has to be the governance nonce in the state, so that governance txs can't be replayed.
+	Nonce uint64               `json:"nonce"`
+	Type  GovernanceActionType `json:"type"`
+	// PublicKey is the reporter to add, remove or unjail.
+	PublicKey string `json:"public_key,omitempty"`
+	// ChainID is the chain to enable or disable, or to set the quorum threshold of. If it is empty,
+	// set_quorum_threshold sets the global threshold.
+	ChainID string `json:"chain_id,omitempty"`
+	// Threshold is the new quorum threshold, or the admin threshold for set_admins. A chain's threshold
+	// can be set to 0 to use the global threshold again.
+	Threshold uint32 `json:"threshold,omitempty"`
+	// Admins is the new admin set for set_admins.
+	Admins []string `json:"admins,omitempty"`
+}
+
+// NewGovernanceTransaction wraps a governance action in a tx, which has to be signed by the admins.
+func NewGovernanceTransaction(action GovernanceAction) (Transaction, error) {
+	payload, err := json.Marshal(action)
+	if err != nil {
+		return Transaction{}, err
+	}
+	return Transaction{
+		Version:     TxFormatVersion,
+		ChainID:     GovernanceChainID,
+		PayloadType: PayloadTypeGovernance,
+		Payload:     payload,
+	}, nil
+}
+
+// GovernanceRecordKey is the state key of the audit record of the governance tx with the given nonce.
+func GovernanceRecordKey(nonce uint64) string {
+	return fmt.Sprintf("%s%020d", governanceRecordPrefix, nonce)
+}
+
+const governanceRecordPrefix = "governance/audit/"
+
+// GovernanceRecord is the audit record of an executed governance tx.
+type GovernanceRecord struct {
+	RollupHeight uint32           `json:"rollup_height"`
+	Action       GovernanceAction `json:"action"`
+	// Signers are the hex encoded public keys of the admins which signed the tx.
+	Signers []string `json:"signers"`
+}
+
+// GetGovernanceRecords returns the audit records of all executed governance txs ordered by nonce.
+func GetGovernanceRecords(state *State) ([]GovernanceRecord, error) {
+	records := []GovernanceRecord{}
+	for _, key := range state.KeysWithPrefix(governanceRecordPrefix) {
+		value, _ := state.Get(key)
+		record := GovernanceRecord{}
+		if err := json.Unmarshal(value, &record); err != nil {
+			return nil, err
+		}
+		records = append(records, record)
+	}
+	return records, nil
+}
+
+// applyGovernanceTx executes a governance tx signed by at least the threshold of admins and records it in the
+// audit trail.
+func applyGovernanceTx(state StateReadWriter, height uint32, tx *Transaction) error {
+	if tx.ChainID != GovernanceChainID {
+		return fmt.Errorf("governance transaction for chain %s", tx.ChainID)
+	}
+	admins, err := GetAdminSet(state)
+	if err != nil {
+		return err
+	}
+	if admins == nil {
+		return errors.New("governance is disabled")
+	}
+	signers, err := governanceSigners(tx, admins)
+	if err != nil {
+		return err
+	}
+
+	action := GovernanceAction{}
+	if err := json.Unmarshal(tx.Payload, &action); err != nil {
+		return fmt.Errorf("failed to decode governance action: %w", err)
+	}
+	nonce, err := getGovernanceNonce(state)
+	if err != nil {
+		return err
+	}
+	if action.Nonce != nonce {
+		return fmt.Errorf("invalid governance nonce %d, expected %d", action.Nonce, nonce)
+	}
+	if err := executeGovernanceAction(state, &action); err != nil {
+		return fmt.Errorf("%s: %w", action.Type, err)
+	}
+
+	value, err := json.Marshal(GovernanceRecord{
+		RollupHeight: height,
+		Action:       action,
+		Signers:      signers,
+	})
+	if err != nil {
+		return err
+	}
+	state.Set(GovernanceRecordKey(nonce), value)
+	return setGovernanceNonce(state, nonce+1)
+}
+
+// governanceSigners checks that the signers of the tx are distinct admins reaching the admin threshold.
+func governanceSigners(tx *Transaction, admins *AdminSet) ([]string, error) {
+	signer, err := tx.VerifySignature()
+	if err != nil {
+		return nil, err
+	}
+	cosigners, err := tx.VerifyCosignatures()
+	if err != nil {
+		return nil, err
+	}
+	signers := []string{}
+	for _, publicKey := range append([]ed25519.PublicKey{signer}, cosigners...) {
+		key := hex.EncodeToString(publicKey)
+		if !slices.Contains(admins.Admins, key) {
+			return nil, fmt.Errorf("signer %s is not an admin", key)
+		}
+		if slices.Contains(signers, key) {
+			return nil, fmt.Errorf("admin %s signed more than once", key)
+		}
+		signers = append(signers, key)
+	}
+	if uint32(len(signers)) < admins.Threshold {
+		return nil, fmt.Errorf("%d admin signatures, need %d", len(signers), admins.Threshold)
+	}
+	return signers, nil
+}
+
+func executeGovernanceAction(state StateReadWriter, action *GovernanceAction) error {
+	switch action.Type {
+	case ActionAddReporter, ActionRemoveReporter, ActionUnjailReporter:
+		publicKey, err := decodePublicKey(action.PublicKey)
+		if err != nil {
+			return fmt.Errorf("invalid reporter %s: %w", action.PublicKey, err)
+		}
+		action.PublicKey = hex.EncodeToString(publicKey)
+		reporter, err := GetReporter(state, action.PublicKey)
+		if err != nil {
+			return err
+		}
+		if action.Type == ActionAddReporter {
+			if reporter != nil {
+				return fmt.Errorf("reporter %s is already in the reporter set", action.PublicKey)
+			}
+			return setReporter(state, Reporter{PublicKey: action.PublicKey})
+		}
+		if reporter == nil {
+			return fmt.Errorf("reporter %s is not in the reporter set", action.PublicKey)
+		}
+		if action.Type == ActionRemoveReporter {
+			state.Delete(ReporterKey(action.PublicKey))
+			return nil
+		}
+		if !reporter.Jailed {
+			return fmt.Errorf("reporter %s is not jailed", action.PublicKey)
+		}
+		reporter.Jailed = false
+		return setReporter(state, *reporter)
+
+	case ActionSetQuorumThreshold:
+		if action.ChainID == "" {
+			if action.Threshold == 0 {
+				return errors.New("quorum threshold must be at least 1")
+			}
+			return setQuorumThreshold(state, action.Threshold)
+		}
+		if err := validateGovernedChainID(action.ChainID); err != nil {
+			return err
+		}
+		config, err := GetChainConfig(state, action.ChainID)
+		if err != nil {
+			return err
+		}
+		config.QuorumThreshold = action.Threshold
+		return setChainConfig(state, action.ChainID, config)
+
+	case ActionEnableChain, ActionDisableChain:
+		if err := validateGovernedChainID(action.ChainID); err != nil {
+			return err
+		}
+		config, err := GetChainConfig(state, action.ChainID)
+		if err != nil {
+			return err
+		}
+		config.Disabled = action.Type == ActionDisableChain
+		return setChainConfig(state, action.ChainID, config)
+
+	case ActionSetAdmins:
+		admins := AdminSet{Admins: action.Admins, Threshold: action.Threshold}
+		if err := admins.validate(); err != nil {
+			return err
+		}
+		return setAdminSet(state, admins)
+
+	default:
+		return fmt.Errorf("unknown governance action %q", action.Type)
+	}
+}
+
+// validateGovernedChainID checks the chain id of a per chain governance action. Chain ids end up in state keys, so they
+// are held to the same rules as those of reports, and the governance chain id is reserved.
+func validateGovernedChainID(chainID string) error {
+	if chainID == GovernanceChainID {
+		return fmt.Errorf("chain id %s is reserved for governance", GovernanceChainID)
+	}
+	return validateChainID(chainID)
+}
@@ -0,0 +1,46 @@
+package rollup
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGovernanceRejectsInvalidChainIDs(t *testing.T) {
+	admin := testReporter(10)
+	genesis := testGenesis(1, testReporter(1))
+	genesis.Admins = []string{testPublicKey(admin)}
+	r, err := NewRollup(NewMemoryStore(), genesis, make(chan Block, 100))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, c := range []struct {
+		name   string
+		action GovernanceAction
+		err    string
+	}{
+		{"enable without chain id", GovernanceAction{Type: ActionEnableChain}, "missing chain id"},
+		{"enable chain id with a /", GovernanceAction{Type: ActionEnableChain, ChainID: "eth/head"}, "contains a /"},
+		{"disable governance chain", GovernanceAction{Type: ActionDisableChain, ChainID: GovernanceChainID}, "reserved for governance"},
+		{"disable chain id with a /", GovernanceAction{Type: ActionDisableChain, ChainID: "eth/head"}, "contains a /"},
+		{"threshold of chain id with a /", GovernanceAction{Type: ActionSetQuorumThreshold, ChainID: "eth/head", Threshold: 1}, "contains a /"},
+		{"threshold of governance chain", GovernanceAction{Type: ActionSetQuorumThreshold, ChainID: GovernanceChainID, Threshold: 1}, "reserved for governance"},
+		{"valid chain id", GovernanceAction{Type: ActionDisableChain, ChainID: "eth-sepolia"}, ""},
+	} {
+		state := r.Snapshot().tipState.Clone()
+		root := state.Root()
+		receipts := ApplyTxs(state, 1, [][]byte{testGovernanceTx(t, admin, c.action)})
+		if c.err == "" {
+			if receipts[0].Status != ReceiptAccepted {
+				t.Errorf("%s: receipt %+v, expected the action to be accepted", c.name, receipts[0])
+			}
+			continue
+		}
+		if receipts[0].Status != ReceiptRejected || !strings.Contains(receipts[0].Reason, c.err) {
+			t.Errorf("%s: receipt %+v, expected a rejection with %q", c.name, receipts[0], c.err)
+		}
+		if state.Root() != root {
+			t.Errorf("%s: the rejected action changed the state", c.name)
+		}
+	}
+}
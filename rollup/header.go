@@ -0,0 +1,12 @@
+package rollup
+
+import (
+	"blockchain-oracle/stateproof"
+)
+
+// BlockVersion is the version of the block header format.
+const BlockVersion = stateproof.BlockVersion
+
+// BlockHeader contains all the fields a block hash commits to. It is defined in the stateproof package, so that
+// light clients can hash headers without importing the rollup.
+type BlockHeader = stateproof.BlockHeader
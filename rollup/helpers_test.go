@@ -0,0 +1,95 @@
+package rollup
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"testing"
+	"time"
+)
+
+// testReporter returns a deterministic reporter key.
+func testReporter(seed byte) ed25519.PrivateKey {
+	return ed25519.NewKeyFromSeed(append(make([]byte, 31), seed))
+}
+
+func testPublicKey(key ed25519.PrivateKey) string {
+	return hex.EncodeToString(key.Public().(ed25519.PublicKey))
+}
+
+// testHash returns a distinct non-zero 0x prefixed hash for each label and number.
+func testHash(label string, n uint64) string {
+	hash := sha256.Sum256(binary.BigEndian.AppendUint64([]byte(label), n))
+	return "0x" + hex.EncodeToString(hash[:])
+}
+
+// testGenesis returns a genesis with the given reporters and quorum threshold.
+func testGenesis(threshold uint32, reporters ...ed25519.PrivateKey) *Genesis {
+	genesis := DefaultGenesis("test-rollup")
+	for _, reporter := range reporters {
+		genesis.Reporters = append(genesis.Reporters, testPublicKey(reporter))
+	}
+	genesis.QuorumThreshold = threshold
+	return genesis
+}
+
+// testEthBlock returns the data of a beacon block at the given slot, which builds on the block at the parent slot.
+func testEthBlock(slot uint64, parentSlot uint64) EthBlockData {
+	return EthBlockData{
+		BlockRoot:  testHash("block", slot),
+		StateRoot:  testHash("state", slot),
+		ParentRoot: testHash("block", parentSlot),
+		Slot:       slot,
+	}
+}
+
+// testEthTx returns an ethereum report tx of the given finality signed by the reporter.
+func testEthTx(t testing.TB, reporter ed25519.PrivateKey, finality Finality, data EthBlockData) []byte {
+	t.Helper()
+	tx, err := NewEthTransaction(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx.Finality = finality
+	if err := tx.Sign(reporter); err != nil {
+		t.Fatal(err)
+	}
+	raw, err := tx.Bytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return raw
+}
+
+// executeTestBlock executes the txs on top of the latest block and makes the new block soft.
+func executeTestBlock(t testing.TB, r *Rollup, txs ...[]byte) *Block {
+	t.Helper()
+	snapshot := r.Snapshot()
+	latest := snapshot.GetLatestBlock()
+	block, err := r.ExecuteBlock(latest.Hash[:], txs, latest.Timestamp.Add(time.Second))
+	if err != nil {
+		t.Fatal(err)
+	}
+	firm, err := r.Snapshot().GetFirmBlock()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.UpdateCommitment(block.Height, firm.Height, firm.Hash[:]); err != nil {
+		t.Fatal(err)
+	}
+	return block
+}
+
+// firmTestBlock makes the block at the given height firm.
+func firmTestBlock(t testing.TB, r *Rollup, height uint32) {
+	t.Helper()
+	snapshot := r.Snapshot()
+	block, err := snapshot.GetBlock(height)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.UpdateCommitment(max(snapshot.Soft(), height), height, block.Hash[:]); err != nil {
+		t.Fatal(err)
+	}
+}
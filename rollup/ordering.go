@@ -0,0 +1,93 @@
+package rollup
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/json"
+	"sort"
+)
+
+// txOrderKey is what txs are ordered by in a block, see OrderTxs.
+type txOrderKey struct {
+	// class orders governance txs before reports, and reports before txs which fail to decode
+	class        int
+	chainID      string
+	finality     Finality
+	reportHeight uint64
+	reporter     string
+	hash         [32]byte
+}
+
+const (
+	txClassGovernance = iota
+	txClassReport
+	txClassInvalid
+)
+
+func newTxOrderKey(raw []byte) txOrderKey {
+	key := txOrderKey{class: txClassInvalid, hash: sha256.Sum256(raw)}
+	tx, err := DecodeTransaction(raw)
+	if err != nil {
+		return key
+	}
+	if tx.PayloadType == PayloadTypeGovernance {
+		action := GovernanceAction{}
+		if err := json.Unmarshal(tx.Payload, &action); err != nil {
+			return key
+		}
+		key.class = txClassGovernance
+		key.reportHeight = action.Nonce
+		return key
+	}
+	payload, err := tx.DecodePayload()
+	if err != nil {
+		return key
+	}
+	key.class = txClassReport
+	key.chainID = tx.ChainID
+	key.finality = tx.Finality.orHead()
+	key.reportHeight = payload.ReportHeight()
+	key.reporter = tx.Reporter
+	return key
+}
+
+func (k *txOrderKey) less(other *txOrderKey) bool {
+	if k.class != other.class {
+		return k.class < other.class
+	}
+	if k.chainID != other.chainID {
+		return k.chainID < other.chainID
+	}
+	if k.finality != other.finality {
+		return k.finality < other.finality
+	}
+	if k.reportHeight != other.reportHeight {
+		return k.reportHeight < other.reportHeight
+	}
+	if k.reporter != other.reporter {
+		return k.reporter < other.reporter
+	}
+	return bytes.Compare(k.hash[:], other.hash[:]) < 0
+}
+
+// OrderTxs returns the txs in the canonical order they are executed and stored in, so that the state after a block
+// doesn't depend on how the sequencer ordered its txs. Governance txs come first ordered by nonce, so that the
+// reports of a block are checked against the updated rules. Reports are grouped by chain and finality level and
+// ordered by slot or block height and then reporter. Txs which fail to decode come last. Ties are broken by tx hash.
+func OrderTxs(txs [][]byte) [][]byte {
+	keys := make([]txOrderKey, len(txs))
+	indexes := make([]int, len(txs))
+	for i, tx := range txs {
+		keys[i] = newTxOrderKey(tx)
+		indexes[i] = i
+	}
+	sort.Slice(indexes, func(a, b int) bool {
+		return keys[indexes[a]].less(&keys[indexes[b]])
+	})
+
+	ordered := make([][]byte, len(txs))
+	for i, index := range indexes {
+		ordered[i] = txs[index]
+	}
+	return ordered
+}
@@ -0,0 +1,140 @@
+package rollup
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"math/rand"
+	"testing"
+)
+
+// testGovernanceTx returns a governance tx signed by the admin.
+func testGovernanceTx(t *testing.T, admin ed25519.PrivateKey, action GovernanceAction) []byte {
+	t.Helper()
+	tx, err := NewGovernanceTransaction(action)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := tx.Sign(admin); err != nil {
+		t.Fatal(err)
+	}
+	raw, err := tx.Bytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return raw
+}
+
+func TestOrderTxsIsIndependentOfSequencing(t *testing.T) {
+	admin := testReporter(10)
+	reporters := []ed25519.PrivateKey{testReporter(1), testReporter(2), testReporter(3)}
+	genesis := testGenesis(2, reporters[:2]...)
+	genesis.Admins = []string{testPublicKey(admin)}
+
+	txs := [][]byte{
+		testGovernanceTx(t, admin, GovernanceAction{Nonce: 0, Type: ActionAddReporter, PublicKey: testPublicKey(reporters[2])}),
+		testGovernanceTx(t, admin, GovernanceAction{Nonce: 1, Type: ActionSetQuorumThreshold, Threshold: 3}),
+		[]byte("not a tx"),
+	}
+	for _, reporter := range reporters {
+		for slot := uint64(1); slot <= 3; slot++ {
+			txs = append(txs, testEthTx(t, reporter, FinalityHead, testEthBlock(slot, slot-1)))
+		}
+		txs = append(txs, testEthTx(t, reporter, FinalityFinalized, testEthBlock(32, 0)))
+	}
+	// a reorged head and a finalized equivocation, whose outcome depends on which report is executed first
+	txs = append(txs,
+		testEthTx(t, reporters[0], FinalityHead, testEthBlockVariant(3, 2)),
+		testEthTx(t, reporters[1], FinalityFinalized, testEthBlockVariant(32, 0)),
+	)
+
+	var expected *Block
+	rng := rand.New(rand.NewSource(1))
+	for i := 0; i < 20; i++ {
+		shuffled := append([][]byte{}, txs...)
+		rng.Shuffle(len(shuffled), func(a, b int) { shuffled[a], shuffled[b] = shuffled[b], shuffled[a] })
+		r, err := NewRollup(NewMemoryStore(), genesis, make(chan Block, 100))
+		if err != nil {
+			t.Fatal(err)
+		}
+		block := executeTestBlock(t, r, shuffled...)
+		if expected == nil {
+			expected = block
+			continue
+		}
+		if block.StateRoot != expected.StateRoot || block.TxRoot != expected.TxRoot || block.Hash != expected.Hash {
+			t.Fatalf("shuffle %d: block %x with state root %x, expected block %x with state root %x",
+				i, block.Hash, block.StateRoot, expected.Hash, expected.StateRoot)
+		}
+	}
+	if len(expected.Receipts) != len(txs) {
+		t.Fatalf("%d receipts for %d txs", len(expected.Receipts), len(txs))
+	}
+}
+
+func TestOrderTxsExecutesGovernanceBeforeReports(t *testing.T) {
+	admin := testReporter(10)
+	reporter := testReporter(2)
+	genesis := testGenesis(1, testReporter(1))
+	genesis.Admins = []string{testPublicKey(admin)}
+
+	report := testEthTx(t, reporter, FinalityHead, testEthBlock(1, 0))
+	addReporter := testGovernanceTx(t, admin, GovernanceAction{Nonce: 0, Type: ActionAddReporter, PublicKey: testPublicKey(reporter)})
+	ordered := OrderTxs([][]byte{report, addReporter})
+	if !bytes.Equal(ordered[0], addReporter) || !bytes.Equal(ordered[1], report) {
+		t.Fatal("the report was ordered before the governance tx")
+	}
+
+	// the report of the reporter added in the same block is accepted, although it was sequenced first
+	r, err := NewRollup(NewMemoryStore(), genesis, make(chan Block, 100))
+	if err != nil {
+		t.Fatal(err)
+	}
+	block := executeTestBlock(t, r, report, addReporter)
+	for _, receipt := range block.Receipts {
+		if receipt.Status != ReceiptAccepted {
+			t.Fatalf("receipt %+v, expected all txs to be accepted", receipt)
+		}
+	}
+	record, err := GetChainRecord(r.Snapshot().tipState, EthereumChainID, FinalityHead)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if record == nil || record.ReportHeight != 1 {
+		t.Fatalf("latest record %+v, expected slot 1", record)
+	}
+}
+
+func TestOrderTxsBreaksTiesByHash(t *testing.T) {
+	reporter := testReporter(1)
+	// the same reporter, chain, finality and slot, so only the hashes differ
+	a := testEthTx(t, reporter, FinalityHead, testEthBlock(1, 0))
+	b := testEthTx(t, reporter, FinalityHead, testEthBlockVariant(1, 0))
+	hashA, hashB := sha256.Sum256(a), sha256.Sum256(b)
+	first, second := a, b
+	if bytes.Compare(hashB[:], hashA[:]) < 0 {
+		first, second = b, a
+	}
+	for _, txs := range [][][]byte{{a, b}, {b, a}} {
+		ordered := OrderTxs(txs)
+		if !bytes.Equal(ordered[0], first) || !bytes.Equal(ordered[1], second) {
+			t.Fatal("txs with the same order key aren't ordered by hash")
+		}
+	}
+
+	// reports are ordered by slot and then reporter before the hash
+	other := testReporter(2)
+	txs := [][]byte{
+		testEthTx(t, reporter, FinalityHead, testEthBlock(2, 1)),
+		testEthTx(t, other, FinalityHead, testEthBlock(1, 0)),
+		testEthTx(t, reporter, FinalityHead, testEthBlock(1, 0)),
+	}
+	ordered := OrderTxs(txs)
+	firstReporter, secondReporter := txs[2], txs[1]
+	if testPublicKey(other) < testPublicKey(reporter) {
+		firstReporter, secondReporter = txs[1], txs[2]
+	}
+	if !bytes.Equal(ordered[0], firstReporter) || !bytes.Equal(ordered[1], secondReporter) || !bytes.Equal(ordered[2], txs[0]) {
+		t.Fatal("reports aren't ordered by slot and reporter")
+	}
+}
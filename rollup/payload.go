@@ -0,0 +1,259 @@
+package rollup
+
+import (
+	"encoding/json"
+	"sync"
+)
+
+type PayloadType string
+
+const (
+	PayloadTypeEthBlock    PayloadType = "eth_block"
+	PayloadTypeBtcBlock    PayloadType = "btc_block"
+	PayloadTypeCosmosBlock PayloadType = "cosmos_block"
+)
+
+// Payload is the decoded payload of a tx, i.e. a report about a block of some chain.
+type Payload interface {
+	// ReportHeight is the slot or block height the report is for. The history of a chain is keyed by it.
+	ReportHeight() uint64
+}
+
+// ProtoPayload is a Payload with a protobuf encoding. Payloads which don't implement it are sequenced as JSON
+// inside the protobuf tx envelope. MarshalProto fails for payloads which can't be encoded, e.g. invalid hex hashes.
+type ProtoPayload interface {
+	Payload
+	MarshalProto() ([]byte, error)
+}
+
+// PayloadDecoder decodes the payload of a tx.
+type PayloadDecoder func(payload []byte) (Payload, error)
+
+var (
+	payloadDecodersLock sync.RWMutex
+	payloadDecoders     = map[PayloadType]PayloadDecoder{
+		PayloadTypeEthBlock:    jsonPayloadDecoder[EthBlockData](),
+		PayloadTypeBtcBlock:    jsonPayloadDecoder[BtcBlockData](),
+		PayloadTypeCosmosBlock: jsonPayloadDecoder[CosmosBlockData](),
+	}
+	protoPayloadDecoders = map[PayloadType]PayloadDecoder{
+		PayloadTypeEthBlock:    protoPayloadDecoder[EthBlockData](),
+		PayloadTypeBtcBlock:    protoPayloadDecoder[BtcBlockData](),
+		PayloadTypeCosmosBlock: protoPayloadDecoder[CosmosBlockData](),
+	}
+)
+
+// RegisterPayloadDecoder registers the decoder for a payload type, replacing any existing decoder.
+// Every node has to register the same decoders, otherwise they will execute blocks differently.
+func RegisterPayloadDecoder(payloadType PayloadType, decoder PayloadDecoder) {
+	payloadDecodersLock.Lock()
+	defer payloadDecodersLock.Unlock()
+	payloadDecoders[payloadType] = decoder
+}
+
+// RegisterProtoPayloadDecoder registers the decoder for the protobuf encoding of a payload type, which has to
+// accept anything the MarshalProto method of the payload returns.
+func RegisterProtoPayloadDecoder(payloadType PayloadType, decoder PayloadDecoder) {
+	payloadDecodersLock.Lock()
+	defer payloadDecodersLock.Unlock()
+	protoPayloadDecoders[payloadType] = decoder
+}
+
+func GetPayloadDecoder(payloadType PayloadType) (PayloadDecoder, bool) {
+	payloadDecodersLock.RLock()
+	defer payloadDecodersLock.RUnlock()
+	decoder, ok := payloadDecoders[payloadType]
+	return decoder, ok
+}
+
+func GetProtoPayloadDecoder(payloadType PayloadType) (PayloadDecoder, bool) {
+	payloadDecodersLock.RLock()
+	defer payloadDecodersLock.RUnlock()
+	decoder, ok := protoPayloadDecoders[payloadType]
+	return decoder, ok
+}
+
+// jsonPayloadDecoder returns a decoder which unmarshals JSON payloads into T.
+func jsonPayloadDecoder[T any, P interface {
+	*T
+	Payload
+}]() PayloadDecoder {
+	return func(payload []byte) (Payload, error) {
+		p := P(new(T))
+		if err := json.Unmarshal(payload, p); err != nil {
+			return nil, err
+		}
+		return p, nil
+	}
+}
+
+// protoPayloadDecoder returns a decoder which unmarshals protobuf payloads into T.
+func protoPayloadDecoder[T any, P interface {
+	*T
+	Payload
+	UnmarshalProto(msg []byte) error
+}]() PayloadDecoder {
+	return func(payload []byte) (Payload, error) {
+		p := P(new(T))
+		if err := p.UnmarshalProto(payload); err != nil {
+			return nil, err
+		}
+		return p, nil
+	}
+}
+
+func (d *EthBlockData) ReportHeight() uint64 {
+	return d.Slot
+}
+
+func (d *EthBlockData) MarshalProto() ([]byte, error) {
+	var err error
+	var b []byte
+	b = appendHexField(b, 1, d.BlockHash, &err)
+	b = appendHexField(b, 2, d.StateRoot, &err)
+	b = appendHexField(b, 3, d.ParentRoot, &err)
+	b = appendVarintField(b, 4, d.Slot)
+	b = appendVarintField(b, 5, d.ProposerIndex)
+	b = appendHexField(b, 6, d.BlockRoot, &err)
+	b = appendVarintField(b, 7, d.BlockNumber)
+	b = appendHexField(b, 8, d.ExecutionStateRoot, &err)
+	b = appendHexField(b, 9, d.ReceiptsRoot, &err)
+	b = appendVarintField(b, 10, d.Timestamp)
+	b = appendStringField(b, 11, d.BaseFeePerGas)
+	b = appendVarintField(b, 12, d.GasUsed)
+	b = appendVarintField(b, 13, d.GasLimit)
+	b = appendHexField(b, 14, d.WithdrawalsRoot, &err)
+	b = appendVarintField(b, 15, d.BlobGasUsed)
+	b = appendVarintField(b, 16, d.ExcessBlobGas)
+	return b, err
+}
+
+func (d *EthBlockData) UnmarshalProto(msg []byte) error {
+	*d = EthBlockData{}
+	return consumeProtoFields(msg, func(field protoField) error {
+		var err error
+		switch field.Num {
+		case 1:
+			d.BlockHash, err = field.hexString(formatEthHash)
+		case 2:
+			d.StateRoot, err = field.hexString(formatEthHash)
+		case 3:
+			d.ParentRoot, err = field.hexString(formatEthHash)
+		case 4:
+			d.Slot, err = field.uint64()
+		case 5:
+			d.ProposerIndex, err = field.uint64()
+		case 6:
+			d.BlockRoot, err = field.hexString(formatEthHash)
+		case 7:
+			d.BlockNumber, err = field.uint64()
+		case 8:
+			d.ExecutionStateRoot, err = field.hexString(formatEthHash)
+		case 9:
+			d.ReceiptsRoot, err = field.hexString(formatEthHash)
+		case 10:
+			d.Timestamp, err = field.uint64()
+		case 11:
+			d.BaseFeePerGas, err = field.string()
+		case 12:
+			d.GasUsed, err = field.uint64()
+		case 13:
+			d.GasLimit, err = field.uint64()
+		case 14:
+			d.WithdrawalsRoot, err = field.hexString(formatEthHash)
+		case 15:
+			d.BlobGasUsed, err = field.uint64()
+		case 16:
+			d.ExcessBlobGas, err = field.uint64()
+		}
+		return err
+	})
+}
+
+// BtcBlockData is a report of a bitcoin block.
+type BtcBlockData struct {
+	BlockHash     string `json:"block_hash"`
+	PrevBlockHash string `json:"prev_block_hash"`
+	MerkleRoot    string `json:"merkle_root"`
+	Height        uint64 `json:"height"`
+	Timestamp     uint64 `json:"timestamp"`
+}
+
+func (d *BtcBlockData) ReportHeight() uint64 {
+	return d.Height
+}
+
+func (d *BtcBlockData) MarshalProto() ([]byte, error) {
+	var err error
+	var b []byte
+	b = appendHexField(b, 1, d.BlockHash, &err)
+	b = appendHexField(b, 2, d.PrevBlockHash, &err)
+	b = appendHexField(b, 3, d.MerkleRoot, &err)
+	b = appendVarintField(b, 4, d.Height)
+	b = appendVarintField(b, 5, d.Timestamp)
+	return b, err
+}
+
+func (d *BtcBlockData) UnmarshalProto(msg []byte) error {
+	*d = BtcBlockData{}
+	return consumeProtoFields(msg, func(field protoField) error {
+		var err error
+		switch field.Num {
+		case 1:
+			d.BlockHash, err = field.hexString(formatBtcHash)
+		case 2:
+			d.PrevBlockHash, err = field.hexString(formatBtcHash)
+		case 3:
+			d.MerkleRoot, err = field.hexString(formatBtcHash)
+		case 4:
+			d.Height, err = field.uint64()
+		case 5:
+			d.Timestamp, err = field.uint64()
+		}
+		return err
+	})
+}
+
+// CosmosBlockData is a report of a block of a cosmos sdk chain.
+type CosmosBlockData struct {
+	BlockHash     string `json:"block_hash"`
+	LastBlockHash string `json:"last_block_hash"`
+	AppHash       string `json:"app_hash"`
+	Height        uint64 `json:"height"`
+	Timestamp     uint64 `json:"timestamp"`
+}
+
+func (d *CosmosBlockData) ReportHeight() uint64 {
+	return d.Height
+}
+
+func (d *CosmosBlockData) MarshalProto() ([]byte, error) {
+	var err error
+	var b []byte
+	b = appendHexField(b, 1, d.BlockHash, &err)
+	b = appendHexField(b, 2, d.LastBlockHash, &err)
+	b = appendHexField(b, 3, d.AppHash, &err)
+	b = appendVarintField(b, 4, d.Height)
+	b = appendVarintField(b, 5, d.Timestamp)
+	return b, err
+}
+
+func (d *CosmosBlockData) UnmarshalProto(msg []byte) error {
+	*d = CosmosBlockData{}
+	return consumeProtoFields(msg, func(field protoField) error {
+		var err error
+		switch field.Num {
+		case 1:
+			d.BlockHash, err = field.hexString(formatCosmosHash)
+		case 2:
+			d.LastBlockHash, err = field.hexString(formatCosmosHash)
+		case 3:
+			d.AppHash, err = field.hexString(formatCosmosHash)
+		case 4:
+			d.Height, err = field.uint64()
+		case 5:
+			d.Timestamp, err = field.uint64()
+		}
+		return err
+	})
+}
@@ -0,0 +1,248 @@
+package rollup
+
+import (
+	"blockchain-oracle/merkle"
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/cockroachdb/pebble"
+)
+
+var (
+	blockKeyPrefix = []byte("block/")
+	hashKeyPrefix  = []byte("hash/")
+	heightKey      = []byte("meta/height")
+	softKey        = []byte("meta/soft")
+	firmKey        = []byte("meta/firm")
+	rulesKey       = []byte("meta/rules_version")
+	// the persisted state is stored under its keys with the state prefix, see UpdateState
+	stateKeyPrefix = []byte("state/")
+	stateHeightKey = []byte("meta/state_height")
+)
+
+// PebbleStore is a BlockStore backed by a pebble database on disk.
+type PebbleStore struct {
+	db *pebble.DB
+}
+
+func NewPebbleStore(path string) (*PebbleStore, error) {
+	db, err := pebble.Open(path, &pebble.Options{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open pebble store at %s: %w", path, err)
+	}
+	return &PebbleStore{db: db}, nil
+}
+
+func blockKey(height uint32) []byte {
+	return binary.BigEndian.AppendUint32(append([]byte{}, blockKeyPrefix...), height)
+}
+
+func hashKey(hash [32]byte) []byte {
+	return append(append([]byte{}, hashKeyPrefix...), hash[:]...)
+}
+
+func (p *PebbleStore) getUint32(key []byte) (uint32, error) {
+	value, closer, err := p.db.Get(key)
+	if errors.Is(err, pebble.ErrNotFound) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	defer closer.Close()
+	if len(value) != 4 {
+		return 0, fmt.Errorf("invalid value length %d for key %s", len(value), key)
+	}
+	return binary.BigEndian.Uint32(value), nil
+}
+
+func (p *PebbleStore) Height() (uint32, error) {
+	return p.getUint32(heightKey)
+}
+
+func (p *PebbleStore) GetBlock(height uint32) (*Block, error) {
+	value, closer, err := p.db.Get(blockKey(height))
+	if errors.Is(err, pebble.ErrNotFound) {
+		return nil, ErrBlockNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer closer.Close()
+
+	block, err := DecodeBlock(value)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode block %d: %w", height, err)
+	}
+	return block, nil
+}
+
+func (p *PebbleStore) GetHeightByHash(hash [32]byte) (uint32, error) {
+	value, closer, err := p.db.Get(hashKey(hash))
+	if errors.Is(err, pebble.ErrNotFound) {
+		return 0, ErrBlockNotFound
+	}
+	if err != nil {
+		return 0, err
+	}
+	defer closer.Close()
+	if len(value) != 4 {
+		return 0, fmt.Errorf("invalid height length %d for block %x", len(value), hash)
+	}
+	return binary.BigEndian.Uint32(value), nil
+}
+
+func (p *PebbleStore) PutBlock(block Block) error {
+	height, err := p.Height()
+	if err != nil {
+		return err
+	}
+	if block.Height != height {
+		return errors.New("block height does not match store height")
+	}
+
+	blockBytes, err := EncodeBlock(&block)
+	if err != nil {
+		return err
+	}
+
+	// write the block, its hash index entry and the new height atomically
+	batch := p.db.NewBatch()
+	defer batch.Close()
+	if err := batch.Set(blockKey(block.Height), blockBytes, nil); err != nil {
+		return err
+	}
+	if err := batch.Set(hashKey(block.Hash), binary.BigEndian.AppendUint32(nil, block.Height), nil); err != nil {
+		return err
+	}
+	if err := batch.Set(heightKey, binary.BigEndian.AppendUint32(nil, height+1), nil); err != nil {
+		return err
+	}
+	return batch.Commit(pebble.Sync)
+}
+
+func (p *PebbleStore) DeleteBlocksAbove(height uint32) error {
+	storeHeight, err := p.Height()
+	if err != nil {
+		return err
+	}
+	if height+1 >= storeHeight {
+		return nil
+	}
+
+	batch := p.db.NewBatch()
+	defer batch.Close()
+	for h := height + 1; h < storeHeight; h++ {
+		block, err := p.GetBlock(h)
+		if err != nil {
+			return err
+		}
+		if err := batch.Delete(hashKey(block.Hash), nil); err != nil {
+			return err
+		}
+	}
+	if err := batch.DeleteRange(blockKey(height+1), blockKey(storeHeight), nil); err != nil {
+		return err
+	}
+	if err := batch.Set(heightKey, binary.BigEndian.AppendUint32(nil, height+1), nil); err != nil {
+		return err
+	}
+	return batch.Commit(pebble.Sync)
+}
+
+func (p *PebbleStore) GetCommitment() (uint32, uint32, error) {
+	soft, err := p.getUint32(softKey)
+	if err != nil {
+		return 0, 0, err
+	}
+	firm, err := p.getUint32(firmKey)
+	if err != nil {
+		return 0, 0, err
+	}
+	return soft, firm, nil
+}
+
+func (p *PebbleStore) SetCommitment(soft uint32, firm uint32) error {
+	batch := p.db.NewBatch()
+	defer batch.Close()
+	if err := batch.Set(softKey, binary.BigEndian.AppendUint32(nil, soft), nil); err != nil {
+		return err
+	}
+	if err := batch.Set(firmKey, binary.BigEndian.AppendUint32(nil, firm), nil); err != nil {
+		return err
+	}
+	return batch.Commit(pebble.Sync)
+}
+
+func (p *PebbleStore) GetRulesVersion() (uint32, error) {
+	return p.getUint32(rulesKey)
+}
+
+func (p *PebbleStore) SetRulesVersion(version uint32) error {
+	return p.db.Set(rulesKey, binary.BigEndian.AppendUint32(nil, version), pebble.Sync)
+}
+
+func stateKey(key string) []byte {
+	return append(append([]byte{}, stateKeyPrefix...), key...)
+}
+
+func (p *PebbleStore) LoadState() (*State, uint32, error) {
+	value, closer, err := p.db.Get(stateHeightKey)
+	if errors.Is(err, pebble.ErrNotFound) {
+		return nil, 0, nil
+	}
+	if err != nil {
+		return nil, 0, err
+	}
+	if len(value) != 4 {
+		closer.Close()
+		return nil, 0, fmt.Errorf("invalid value length %d for key %s", len(value), stateHeightKey)
+	}
+	height := binary.BigEndian.Uint32(value)
+	closer.Close()
+
+	iter, err := p.db.NewIter(&pebble.IterOptions{
+		LowerBound: stateKeyPrefix,
+		UpperBound: append(stateKeyPrefix[:len(stateKeyPrefix)-1:len(stateKeyPrefix)-1], stateKeyPrefix[len(stateKeyPrefix)-1]+1),
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+	defer iter.Close()
+	entries := []merkle.SparseEntry{}
+	for iter.First(); iter.Valid(); iter.Next() {
+		entries = append(entries, merkle.SparseEntry{
+			Key:   append([]byte{}, iter.Key()[len(stateKeyPrefix):]...),
+			Value: append([]byte{}, iter.Value()...),
+		})
+	}
+	if err := iter.Error(); err != nil {
+		return nil, 0, err
+	}
+	return newStateFromEntries(entries), height, nil
+}
+
+func (p *PebbleStore) UpdateState(height uint32, changes map[string][]byte) error {
+	batch := p.db.NewBatch()
+	defer batch.Close()
+	for key, value := range changes {
+		var err error
+		if value == nil {
+			err = batch.Delete(stateKey(key), nil)
+		} else {
+			err = batch.Set(stateKey(key), value, nil)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	if err := batch.Set(stateHeightKey, binary.BigEndian.AppendUint32(nil, height), nil); err != nil {
+		return err
+	}
+	return batch.Commit(pebble.Sync)
+}
+
+func (p *PebbleStore) Close() error {
+	return p.db.Close()
+}
@@ -0,0 +1,351 @@
+package rollup
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// EncodingProto is the leading format byte of txs and blocks encoded as the protobuf messages in
+// proto/oracle/v1/oracle.proto. JSON encodings always start with '{', so data without it is decoded as JSON,
+// which was the only encoding before.
+const EncodingProto byte = 0x01
+
+// The messages are encoded by hand with protowire, fields in field number order and zero values omitted,
+// so that the encoding of a message is canonical.
+
+func appendVarintField(b []byte, num protowire.Number, v uint64) []byte {
+	if v == 0 {
+		return b
+	}
+	b = protowire.AppendTag(b, num, protowire.VarintType)
+	return protowire.AppendVarint(b, v)
+}
+
+func appendBytesField(b []byte, num protowire.Number, v []byte) []byte {
+	if len(v) == 0 {
+		return b
+	}
+	b = protowire.AppendTag(b, num, protowire.BytesType)
+	return protowire.AppendBytes(b, v)
+}
+
+func appendStringField(b []byte, num protowire.Number, v string) []byte {
+	return appendBytesField(b, num, []byte(v))
+}
+
+// appendHexField appends a hex encoded hash, which may be 0x prefixed, as a bytes field. It does nothing once *err
+// is set, so that a message with several hashes is encoded with a single error check.
+func appendHexField(b []byte, num protowire.Number, v string, err *error) []byte {
+	if *err != nil {
+		return b
+	}
+	bs, decodeErr := hex.DecodeString(strings.TrimPrefix(v, "0x"))
+	if decodeErr != nil {
+		*err = fmt.Errorf("invalid hex in field %d: %w", num, decodeErr)
+		return b
+	}
+	return appendBytesField(b, num, bs)
+}
+
+// appendMessageField appends an embedded message. Unlike other fields it is written even if it is empty.
+func appendMessageField(b []byte, num protowire.Number, msg []byte) []byte {
+	b = protowire.AppendTag(b, num, protowire.BytesType)
+	return protowire.AppendBytes(b, msg)
+}
+
+type protoField struct {
+	Num    protowire.Number
+	Type   protowire.Type
+	varint uint64
+	bytes  []byte
+}
+
+func (f protoField) typeError() error {
+	return fmt.Errorf("unexpected wire type %d for field %d", f.Type, f.Num)
+}
+
+func (f protoField) uint64() (uint64, error) {
+	if f.Type != protowire.VarintType {
+		return 0, f.typeError()
+	}
+	return f.varint, nil
+}
+
+func (f protoField) uint32() (uint32, error) {
+	v, err := f.uint64()
+	if err != nil {
+		return 0, err
+	}
+	if v > math.MaxUint32 {
+		return 0, fmt.Errorf("field %d overflows uint32", f.Num)
+	}
+	return uint32(v), nil
+}
+
+func (f protoField) bytesValue() ([]byte, error) {
+	if f.Type != protowire.BytesType {
+		return nil, f.typeError()
+	}
+	return f.bytes, nil
+}
+
+func (f protoField) string() (string, error) {
+	v, err := f.bytesValue()
+	return string(v), err
+}
+
+// hexString returns the bytes of the field formatted with format, or an empty string if they are empty.
+func (f protoField) hexString(format func([]byte) string) (string, error) {
+	v, err := f.bytesValue()
+	if err != nil || len(v) == 0 {
+		return "", err
+	}
+	return format(v), nil
+}
+
+// formatEthHash, formatBtcHash and formatCosmosHash format hashes the way the chains' apis do.
+func formatEthHash(bs []byte) string {
+	return "0x" + hex.EncodeToString(bs)
+}
+
+func formatBtcHash(bs []byte) string {
+	return hex.EncodeToString(bs)
+}
+
+func formatCosmosHash(bs []byte) string {
+	return strings.ToUpper(hex.EncodeToString(bs))
+}
+
+func (f protoField) hash() ([32]byte, error) {
+	v, err := f.bytesValue()
+	if err != nil {
+		return [32]byte{}, err
+	}
+	if len(v) != 32 {
+		return [32]byte{}, fmt.Errorf("field %d has length %d, expected 32", f.Num, len(v))
+	}
+	return [32]byte(v), nil
+}
+
+// consumeProtoFields calls fn for each field of an encoded message. Fields of unknown wire types are skipped.
+// The bytes of a field alias msg.
+func consumeProtoFields(msg []byte, fn func(field protoField) error) error {
+	for len(msg) > 0 {
+		num, typ, n := protowire.ConsumeTag(msg)
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		msg = msg[n:]
+
+		field := protoField{Num: num, Type: typ}
+		switch typ {
+		case protowire.VarintType:
+			field.varint, n = protowire.ConsumeVarint(msg)
+		case protowire.BytesType:
+			field.bytes, n = protowire.ConsumeBytes(msg)
+		default:
+			n = protowire.ConsumeFieldValue(num, typ, msg)
+		}
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		msg = msg[n:]
+
+		if err := fn(field); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// marshalTimestamp encodes a time as a google.protobuf.Timestamp.
+func marshalTimestamp(t time.Time) []byte {
+	b := appendVarintField(nil, 1, uint64(t.Unix()))
+	return appendVarintField(b, 2, uint64(t.Nanosecond()))
+}
+
+func unmarshalTimestamp(msg []byte) (time.Time, error) {
+	var seconds int64
+	var nanos int64
+	err := consumeProtoFields(msg, func(field protoField) error {
+		v, err := field.uint64()
+		switch field.Num {
+		case 1:
+			seconds = int64(v)
+		case 2:
+			nanos = int64(int32(v))
+		default:
+			return nil
+		}
+		return err
+	})
+	if err != nil {
+		return time.Time{}, err
+	}
+	if nanos < 0 || nanos >= int64(time.Second) {
+		return time.Time{}, fmt.Errorf("invalid timestamp nanos %d", nanos)
+	}
+	return time.Unix(seconds, nanos).UTC(), nil
+}
+
+func marshalHeaderProto(h *BlockHeader) []byte {
+	b := appendVarintField(nil, 1, uint64(h.Version))
+	b = appendBytesField(b, 2, h.ParentHash[:])
+	b = appendVarintField(b, 3, uint64(h.Height))
+	b = appendMessageField(b, 4, marshalTimestamp(h.Timestamp))
+	b = appendBytesField(b, 5, h.TxRoot[:])
+	return appendBytesField(b, 6, h.StateRoot[:])
+}
+
+func unmarshalHeaderProto(h *BlockHeader, msg []byte) error {
+	*h = BlockHeader{}
+	return consumeProtoFields(msg, func(field protoField) error {
+		var err error
+		switch field.Num {
+		case 1:
+			h.Version, err = field.uint32()
+		case 2:
+			h.ParentHash, err = field.hash()
+		case 3:
+			h.Height, err = field.uint32()
+		case 4:
+			var ts []byte
+			if ts, err = field.bytesValue(); err == nil {
+				h.Timestamp, err = unmarshalTimestamp(ts)
+			}
+		case 5:
+			h.TxRoot, err = field.hash()
+		case 6:
+			h.StateRoot, err = field.hash()
+		}
+		return err
+	})
+}
+
+var receiptStatusProtoValues = map[ReceiptStatus]uint64{
+	ReceiptAccepted:  1,
+	ReceiptRejected:  2,
+	ReceiptJailed:    3,
+	ReceiptDuplicate: 4,
+}
+
+func (r *Receipt) MarshalProto() ([]byte, error) {
+	status, ok := receiptStatusProtoValues[r.Status]
+	if !ok {
+		return nil, fmt.Errorf("unknown receipt status %q", r.Status)
+	}
+	b := appendVarintField(nil, 1, uint64(r.TxIndex))
+	b = appendBytesField(b, 2, r.TxHash[:])
+	b = appendVarintField(b, 3, status)
+	b = appendStringField(b, 4, r.Reason)
+	for _, key := range r.ChangedKeys {
+		b = protowire.AppendTag(b, 5, protowire.BytesType)
+		b = protowire.AppendString(b, key)
+	}
+	return b, nil
+}
+
+func (r *Receipt) UnmarshalProto(msg []byte) error {
+	*r = Receipt{ChangedKeys: []string{}}
+	return consumeProtoFields(msg, func(field protoField) error {
+		var err error
+		switch field.Num {
+		case 1:
+			r.TxIndex, err = field.uint32()
+		case 2:
+			r.TxHash, err = field.hash()
+		case 3:
+			var status uint64
+			if status, err = field.uint64(); err != nil {
+				return err
+			}
+			for s, v := range receiptStatusProtoValues {
+				if v == status {
+					r.Status = s
+				}
+			}
+			if r.Status == "" {
+				err = fmt.Errorf("unknown receipt status %d", status)
+			}
+		case 4:
+			r.Reason, err = field.string()
+		case 5:
+			var key string
+			if key, err = field.string(); err == nil {
+				r.ChangedKeys = append(r.ChangedKeys, key)
+			}
+		}
+		return err
+	})
+}
+
+// EncodeBlock encodes a block for storage, prefixed with the protobuf format byte.
+func EncodeBlock(block *Block) ([]byte, error) {
+	b := []byte{EncodingProto}
+	b = appendMessageField(b, 1, marshalHeaderProto(&block.BlockHeader))
+	b = appendBytesField(b, 2, block.Hash[:])
+	for _, tx := range block.Txs {
+		b = protowire.AppendTag(b, 3, protowire.BytesType)
+		b = protowire.AppendBytes(b, tx)
+	}
+	for i := range block.Receipts {
+		receipt, err := block.Receipts[i].MarshalProto()
+		if err != nil {
+			return nil, err
+		}
+		b = appendMessageField(b, 4, receipt)
+	}
+	return b, nil
+}
+
+// DecodeBlock decodes a block encoded by EncodeBlock, falling back to JSON for blocks stored before.
+func DecodeBlock(data []byte) (*Block, error) {
+	if len(data) == 0 {
+		return nil, errors.New("empty block encoding")
+	}
+	if data[0] != EncodingProto {
+		block := &Block{}
+		if err := json.Unmarshal(data, block); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal block: %w", err)
+		}
+		return block, nil
+	}
+
+	block := &Block{Txs: [][]byte{}, Receipts: []Receipt{}}
+	err := consumeProtoFields(data[1:], func(field protoField) error {
+		var err error
+		switch field.Num {
+		case 1:
+			var header []byte
+			if header, err = field.bytesValue(); err == nil {
+				err = unmarshalHeaderProto(&block.BlockHeader, header)
+			}
+		case 2:
+			block.Hash, err = field.hash()
+		case 3:
+			var tx []byte
+			if tx, err = field.bytesValue(); err == nil {
+				block.Txs = append(block.Txs, append([]byte{}, tx...))
+			}
+		case 4:
+			var receiptBytes []byte
+			if receiptBytes, err = field.bytesValue(); err == nil {
+				receipt := Receipt{}
+				err = receipt.UnmarshalProto(receiptBytes)
+				block.Receipts = append(block.Receipts, receipt)
+			}
+		}
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode block: %w", err)
+	}
+	return block, nil
+}
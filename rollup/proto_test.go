@@ -0,0 +1,254 @@
+package rollup
+
+import (
+	oraclev1 "blockchain-oracle/proto/oracle/v1"
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// The hand written codec is checked against the code generated from proto/oracle/v1/oracle.proto: its encodings
+// have to parse without unknown fields, and the generated code's encodings have to decode to the same values.
+
+// checkGenerated unmarshals the encoding into the generated message, checks that every field is known and returns
+// the generated code's encoding of the message. It isn't compared byte for byte, since the generated code writes
+// oneof fields last while the hand written codec writes all fields in field number order.
+func checkGenerated(t *testing.T, encoded []byte, msg proto.Message) []byte {
+	t.Helper()
+	if err := proto.Unmarshal(encoded, msg); err != nil {
+		t.Fatal(err)
+	}
+	checkNoUnknownFields(t, msg.ProtoReflect())
+	reencoded, err := proto.MarshalOptions{Deterministic: true}.Marshal(msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return reencoded
+}
+
+func checkNoUnknownFields(t *testing.T, msg protoreflect.Message) {
+	t.Helper()
+	if len(msg.GetUnknown()) > 0 {
+		t.Fatalf("%s has unknown fields %x", msg.Descriptor().FullName(), msg.GetUnknown())
+	}
+	msg.Range(func(field protoreflect.FieldDescriptor, value protoreflect.Value) bool {
+		switch {
+		case field.Message() == nil:
+		case field.IsList():
+			for i := 0; i < value.List().Len(); i++ {
+				checkNoUnknownFields(t, value.List().Get(i).Message())
+			}
+		default:
+			checkNoUnknownFields(t, value.Message())
+		}
+		return true
+	})
+}
+
+func testEthBlockData() EthBlockData {
+	data := testEthBlock(9000000, 8999999)
+	data.BlockHash = testHash("execution", 1)
+	data.ProposerIndex = 42
+	data.BlockNumber = 20000000
+	data.ExecutionStateRoot = testHash("execution-state", 1)
+	data.ReceiptsRoot = testHash("receipts", 1)
+	data.Timestamp = 1718000000
+	data.BaseFeePerGas = "1234567890123"
+	data.GasUsed = 15000000
+	data.GasLimit = 30000000
+	data.WithdrawalsRoot = testHash("withdrawals", 1)
+	data.BlobGasUsed = 131072
+	data.ExcessBlobGas = 393216
+	return data
+}
+
+func TestTransactionProtoMatchesGenerated(t *testing.T) {
+	tx, err := NewEthTransaction(testEthBlockData())
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx.Finality = FinalityFinalized
+	if err := tx.Sign(testReporter(1)); err != nil {
+		t.Fatal(err)
+	}
+	if err := tx.Cosign(testReporter(2)); err != nil {
+		t.Fatal(err)
+	}
+	raw, err := tx.Bytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if raw[0] != EncodingProto {
+		t.Fatalf("tx starts with %x, expected the format byte", raw[0])
+	}
+
+	generated := &oraclev1.Transaction{}
+	reencoded := checkGenerated(t, raw[1:], generated)
+	if generated.Version != TxFormatVersion || generated.ChainId != EthereumChainID ||
+		generated.PayloadType != string(PayloadTypeEthBlock) || generated.Finality != string(FinalityFinalized) {
+		t.Fatalf("unexpected envelope %v", generated)
+	}
+	if hex.EncodeToString(generated.Reporter) != tx.Reporter || hex.EncodeToString(generated.Signature) != tx.Signature {
+		t.Fatal("reporter or signature differ")
+	}
+	if len(generated.Cosignatures) != 1 || hex.EncodeToString(generated.Cosignatures[0].PublicKey) != tx.Cosignatures[0].PublicKey {
+		t.Fatal("cosignatures differ")
+	}
+	payload, ok := generated.Payload.(*oraclev1.Transaction_ProtoPayload)
+	if !ok {
+		t.Fatalf("payload is %T, expected a protobuf payload", generated.Payload)
+	}
+	payloadEncoded := checkGenerated(t, payload.ProtoPayload, &oraclev1.EthBlockData{})
+	if !bytes.Equal(payloadEncoded, payload.ProtoPayload) {
+		t.Fatalf("generated code encodes the payload as %x, expected %x", payloadEncoded, payload.ProtoPayload)
+	}
+
+	decoded, err := DecodeTransaction(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := decoded.VerifySignature(); err != nil {
+		t.Fatal(err)
+	}
+	fromGenerated, err := DecodeTransaction(append([]byte{EncodingProto}, reencoded...))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(fromGenerated, decoded) {
+		t.Fatalf("decoded %+v from the generated encoding, expected %+v", fromGenerated, decoded)
+	}
+}
+
+func TestEthBlockDataProtoMatchesGenerated(t *testing.T) {
+	data := testEthBlockData()
+	encoded, err := data.MarshalProto()
+	if err != nil {
+		t.Fatal(err)
+	}
+	generated := &oraclev1.EthBlockData{}
+	reencoded := checkGenerated(t, encoded, generated)
+	if !bytes.Equal(reencoded, encoded) {
+		t.Fatalf("generated code encodes %x, expected %x", reencoded, encoded)
+	}
+	if formatEthHash(generated.BlockRoot) != data.BlockRoot || formatEthHash(generated.WithdrawalsRoot) != data.WithdrawalsRoot {
+		t.Fatal("hashes differ")
+	}
+	if generated.Slot != data.Slot || generated.ExcessBlobGas != data.ExcessBlobGas || generated.BaseFeePerGas != data.BaseFeePerGas {
+		t.Fatal("fields differ")
+	}
+
+	decoded := &EthBlockData{}
+	if err := decoded.UnmarshalProto(encoded); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(*decoded, data) {
+		t.Fatalf("decoded %+v, expected %+v", *decoded, data)
+	}
+
+	// hashes are bytes, so they are decoded in the canonical format regardless of how they were reported
+	upper := data
+	upper.BlockRoot = "0x" + strings.ToUpper(data.BlockRoot[2:])
+	upperEncoded, err := upper.MarshalProto()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(upperEncoded, encoded) {
+		t.Fatal("the encoding depends on the case of the hashes")
+	}
+
+	invalid := data
+	invalid.StateRoot = "0xnothex"
+	if _, err := invalid.MarshalProto(); err == nil {
+		t.Fatal("encoded an invalid hash")
+	}
+}
+
+func TestBtcAndCosmosBlockDataProtoMatchesGenerated(t *testing.T) {
+	btc := BtcBlockData{
+		BlockHash:     testHash("btc", 1)[2:],
+		PrevBlockHash: testHash("btc", 0)[2:],
+		MerkleRoot:    testHash("merkle", 1)[2:],
+		Height:        850000,
+		Timestamp:     1718000000,
+	}
+	encoded, err := btc.MarshalProto()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reencoded := checkGenerated(t, encoded, &oraclev1.BtcBlockData{}); !bytes.Equal(reencoded, encoded) {
+		t.Fatalf("generated code encodes %x, expected %x", reencoded, encoded)
+	}
+	decodedBtc := &BtcBlockData{}
+	if err := decodedBtc.UnmarshalProto(encoded); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(*decodedBtc, btc) {
+		t.Fatalf("decoded %+v, expected %+v", *decodedBtc, btc)
+	}
+
+	cosmos := CosmosBlockData{
+		BlockHash:     strings.ToUpper(testHash("cosmos", 1)[2:]),
+		LastBlockHash: strings.ToUpper(testHash("cosmos", 0)[2:]),
+		AppHash:       strings.ToUpper(testHash("app", 1)[2:]),
+		Height:        20000000,
+		Timestamp:     1718000000,
+	}
+	encoded, err = cosmos.MarshalProto()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reencoded := checkGenerated(t, encoded, &oraclev1.CosmosBlockData{}); !bytes.Equal(reencoded, encoded) {
+		t.Fatalf("generated code encodes %x, expected %x", reencoded, encoded)
+	}
+	decodedCosmos := &CosmosBlockData{}
+	if err := decodedCosmos.UnmarshalProto(encoded); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(*decodedCosmos, cosmos) {
+		t.Fatalf("decoded %+v, expected %+v", *decodedCosmos, cosmos)
+	}
+}
+
+func TestBlockProtoMatchesGenerated(t *testing.T) {
+	reporter := testReporter(1)
+	txs := [][]byte{
+		testEthTx(t, reporter, FinalityHead, testEthBlock(1, 0)),
+		[]byte(`{"version":1}`),
+	}
+	receipts := []Receipt{
+		{TxIndex: 0, Status: ReceiptAccepted, ChangedKeys: []string{"chain/ethereum/latest"}},
+		{TxIndex: 1, Status: ReceiptRejected, Reason: "transaction is missing a chain id", ChangedKeys: []string{}},
+	}
+	block := NewBlock(make([]byte, 32), 1, txs, receipts, [32]byte{1}, time.Unix(1718000000, 123).UTC())
+	encoded, err := EncodeBlock(&block)
+	if err != nil {
+		t.Fatal(err)
+	}
+	generated := &oraclev1.Block{}
+	if reencoded := checkGenerated(t, encoded[1:], generated); !bytes.Equal(reencoded, encoded[1:]) {
+		t.Fatalf("generated code encodes %x, expected %x", reencoded, encoded[1:])
+	}
+	if generated.Header.Height != 1 || !generated.Header.Timestamp.AsTime().Equal(block.Timestamp) {
+		t.Fatalf("unexpected header %v", generated.Header)
+	}
+	if generated.Receipts[1].Status != oraclev1.ReceiptStatus_RECEIPT_STATUS_REJECTED {
+		t.Fatalf("unexpected receipt status %v", generated.Receipts[1].Status)
+	}
+
+	decoded, err := DecodeBlock(encoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected, _ := json.Marshal(block)
+	actual, _ := json.Marshal(decoded)
+	if !bytes.Equal(actual, expected) {
+		t.Fatalf("decoded block %s, expected %s", actual, expected)
+	}
+}
@@ -0,0 +1,240 @@
+package rollup
+
+import (
+	"blockchain-oracle/merkle"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"slices"
+)
+
+// QuorumThresholdKey is the state key of the number of distinct reporters which have to submit matching reports
+// for a slot or block height before the report becomes the canonical record of the chain at that height.
+const QuorumThresholdKey = "params/quorum_threshold"
+
+// GetQuorumThreshold returns the quorum threshold, which is 1 if it isn't set.
+func GetQuorumThreshold(state StateReadWriter) (uint32, error) {
+	value, ok := state.Get(QuorumThresholdKey)
+	if !ok {
+		return 1, nil
+	}
+	var threshold uint32
+	if err := json.Unmarshal(value, &threshold); err != nil {
+		return 0, err
+	}
+	return threshold, nil
+}
+
+// GetChainQuorumThreshold returns the quorum threshold of a chain, which is the global threshold unless governance
+// set one for the chain.
+func GetChainQuorumThreshold(state StateReadWriter, chainID string) (uint32, error) {
+	config, err := GetChainConfig(state, chainID)
+	if err != nil {
+		return 0, err
+	}
+	if config.QuorumThreshold > 0 {
+		return config.QuorumThreshold, nil
+	}
+	return GetQuorumThreshold(state)
+}
+
+func setQuorumThreshold(state StateReadWriter, threshold uint32) error {
+	value, err := json.Marshal(threshold)
+	if err != nil {
+		return err
+	}
+	state.Set(QuorumThresholdKey, value)
+	return nil
+}
+
+// PendingReportKey is the state key of the reports for a slot or block height of a finality level of a chain which
+// haven't reached the quorum threshold yet. The height is zero padded so that pending keys sort by height.
+func PendingReportKey(chainID string, finality Finality, reportHeight uint64) string {
+	return fmt.Sprintf("%s%020d", pendingReportPrefix(chainID, finality), reportHeight)
+}
+
+func pendingReportPrefix(chainID string, finality Finality) string {
+	return chainKeyPrefix(chainID, finality) + "pending/"
+}
+
+// PendingCandidate is a report value for a height along with the reporters which submitted it.
+type PendingCandidate struct {
+	// Digest is the hash of the payload type and data. Reports match if their digests match.
+	Digest      merkle.Hash     `json:"digest"`
+	PayloadType PayloadType     `json:"payload_type"`
+	Data        json.RawMessage `json:"data"`
+	Reporters   []string        `json:"reporters"`
+}
+
+// PendingReport is the tally of the reports for a slot or block height of a chain which hasn't reached quorum.
+type PendingReport struct {
+	ChainID      string             `json:"chain_id"`
+	Finality     Finality           `json:"finality,omitempty"`
+	ReportHeight uint64             `json:"report_height"`
+	Candidates   []PendingCandidate `json:"candidates"`
+}
+
+func reportDigest(payloadType PayloadType, data []byte) merkle.Hash {
+	h := sha256.New()
+	h.Write([]byte(payloadType))
+	h.Write([]byte{0})
+	h.Write(data)
+	return merkle.Hash(h.Sum(nil))
+}
+
+// GetPendingReport returns the tally for a height of a finality level of a chain, or nil if there is none.
+func GetPendingReport(state StateReadWriter, chainID string, finality Finality, reportHeight uint64) (*PendingReport, error) {
+	value, ok := state.Get(PendingReportKey(chainID, finality, reportHeight))
+	if !ok {
+		return nil, nil
+	}
+	pending := &PendingReport{}
+	if err := json.Unmarshal(value, pending); err != nil {
+		return nil, err
+	}
+	return pending, nil
+}
+
+// GetPendingReports returns all tallies of a finality level of a chain ordered by height.
+func GetPendingReports(state *State, chainID string, finality Finality) ([]PendingReport, error) {
+	prefix := pendingReportPrefix(chainID, finality)
+	reports := []PendingReport{}
+	for _, key := range state.KeysWithPrefix(prefix) {
+		value, _ := state.Get(key)
+		pending := PendingReport{}
+		if err := json.Unmarshal(value, &pending); err != nil {
+			return nil, err
+		}
+		reports = append(reports, pending)
+	}
+	return reports, nil
+}
+
+// submitReport adds a reporter's report to the tally of its height. The report becomes the canonical record of
+// the chain at that height once the quorum threshold of reporters have submitted matching reports, and the tallies
+// up to that height are removed. Reports have to pass validateReport against the latest record of the chain. Each
+// finality level of a chain is tallied and validated separately.
+//
+// A justified or finalized block never changes, so a reporter which submits a different report for a height it
+// already reported at those levels is jailed, see jailForEquivocation. The head block at a height changes with
+// reorgs, so a different head report replaces the reporter's earlier report in the tally instead.
+func submitReport(state StateReadWriter, record ChainRecord, reporter string, tx *Transaction) error {
+	digest := reportDigest(record.PayloadType, record.Data)
+	evidence := Evidence{
+		ChainID:       record.ChainID,
+		Finality:      record.Finality,
+		ReportHeight:  record.ReportHeight,
+		Reporter:      reporter,
+		RollupHeight:  record.RollupHeight,
+		ConflictingTx: tx,
+	}
+
+	canonical, err := GetHistoryRecord(state, record.ChainID, record.Finality, record.ReportHeight)
+	if err != nil {
+		return err
+	}
+	if canonical != nil {
+		canonicalDigest := reportDigest(canonical.PayloadType, canonical.Data)
+		if slices.Contains(canonical.Reporters, reporter) && canonicalDigest != digest && record.Finality.orHead() != FinalityHead {
+			evidence.PreviousDigest = canonicalDigest
+			evidence.PreviousData = canonical.Data
+			return jailForEquivocation(state, evidence)
+		}
+		return fmt.Errorf("%s height %d already has a canonical record", record.ChainID, record.ReportHeight)
+	}
+	latest, err := GetChainRecord(state, record.ChainID, record.Finality)
+	if err != nil {
+		return err
+	}
+	if err := validateReport(latest, &record); err != nil {
+		return err
+	}
+
+	threshold, err := GetChainQuorumThreshold(state, record.ChainID)
+	if err != nil {
+		return err
+	}
+	pending, err := GetPendingReport(state, record.ChainID, record.Finality, record.ReportHeight)
+	if err != nil {
+		return err
+	}
+	if pending == nil {
+		pending = &PendingReport{
+			ChainID:      record.ChainID,
+			Finality:     record.Finality,
+			ReportHeight: record.ReportHeight,
+			Candidates:   []PendingCandidate{},
+		}
+	}
+
+	index := -1
+	for i, candidate := range pending.Candidates {
+		if slices.Contains(candidate.Reporters, reporter) {
+			if candidate.Digest == digest {
+				return fmt.Errorf("reporter %s already reported %s height %d", reporter, record.ChainID, record.ReportHeight)
+			}
+			// the earlier report of the reporter doesn't count towards the quorum anymore
+			pending.Candidates[i].Reporters = slices.DeleteFunc(candidate.Reporters, func(r string) bool { return r == reporter })
+			if record.Finality.orHead() == FinalityHead {
+				// the head was reorged, the report replaces the earlier one
+				continue
+			}
+			if err := setPendingReport(state, pending); err != nil {
+				return err
+			}
+			evidence.PreviousDigest = candidate.Digest
+			evidence.PreviousData = candidate.Data
+			return jailForEquivocation(state, evidence)
+		}
+		if candidate.Digest == digest {
+			index = i
+		}
+	}
+	if index == -1 {
+		pending.Candidates = append(pending.Candidates, PendingCandidate{
+			Digest:      digest,
+			PayloadType: record.PayloadType,
+			Data:        record.Data,
+			Reporters:   []string{},
+		})
+		index = len(pending.Candidates) - 1
+	}
+	candidate := &pending.Candidates[index]
+	candidate.Reporters = append(candidate.Reporters, reporter)
+
+	if uint32(len(candidate.Reporters)) >= threshold {
+		record.Reporters = candidate.Reporters
+		prunePendingReports(state, record.ChainID, record.Finality, record.ReportHeight)
+		return setChainRecord(state, record)
+	}
+	return setPendingReport(state, pending)
+}
+
+// prunePendingReports removes the tallies of a finality level of a chain at or below the given height, which became
+// canonical. Tallies below it can't reach quorum anymore, since reports have to be above the latest record.
+func prunePendingReports(state StateReadWriter, chainID string, finality Finality, reportHeight uint64) {
+	last := PendingReportKey(chainID, finality, reportHeight)
+	for _, key := range state.KeysWithPrefix(pendingReportPrefix(chainID, finality)) {
+		if key > last {
+			break
+		}
+		state.Delete(key)
+	}
+}
+
+// setPendingReport stores the tally, dropping candidates without reporters. The tally is removed if there are none
+// left.
+func setPendingReport(state StateReadWriter, pending *PendingReport) error {
+	pending.Candidates = slices.DeleteFunc(pending.Candidates, func(c PendingCandidate) bool { return len(c.Reporters) == 0 })
+	key := PendingReportKey(pending.ChainID, pending.Finality, pending.ReportHeight)
+	if len(pending.Candidates) == 0 {
+		state.Delete(key)
+		return nil
+	}
+	value, err := json.Marshal(pending)
+	if err != nil {
+		return err
+	}
+	state.Set(key, value)
+	return nil
+}
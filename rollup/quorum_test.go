@@ -0,0 +1,165 @@
+package rollup
+
+import (
+	"crypto/ed25519"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestQuorumPrunesPendingReports(t *testing.T) {
+	reporters := []ed25519.PrivateKey{testReporter(1), testReporter(2), testReporter(3)}
+	r, err := NewRollup(NewMemoryStore(), testGenesis(2, reporters...), make(chan Block, 100))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// single votes for slots 1 to 3 stay pending
+	block := executeTestBlock(t, r,
+		testEthTx(t, reporters[0], FinalityHead, testEthBlock(1, 0)),
+		testEthTx(t, reporters[1], FinalityHead, testEthBlock(2, 1)),
+		testEthTx(t, reporters[0], FinalityHead, testEthBlock(3, 2)),
+	)
+	if reports := canonicalReports(*block); len(reports) != 0 {
+		t.Fatalf("broadcast %d reports which didn't reach quorum", len(reports))
+	}
+	pending, err := GetPendingReports(r.Snapshot().tipState, EthereumChainID, FinalityHead)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pending) != 3 {
+		t.Fatalf("%d pending reports, expected 3", len(pending))
+	}
+
+	// the second vote for slot 2 makes it canonical, the tallies of slots 1 and 2 are removed
+	block = executeTestBlock(t, r, testEthTx(t, reporters[2], FinalityHead, testEthBlock(2, 1)))
+	reports := canonicalReports(*block)
+	if len(reports) != 1 || reports[0].Reporter != testPublicKey(reporters[2]) {
+		t.Fatalf("broadcast %v, expected the report which reached quorum", reports)
+	}
+	pending, err = GetPendingReports(r.Snapshot().tipState, EthereumChainID, FinalityHead)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pending) != 1 || pending[0].ReportHeight != 3 {
+		t.Fatalf("pending reports %+v, expected only slot 3", pending)
+	}
+	record, err := GetChainRecord(r.Snapshot().tipState, EthereumChainID, FinalityHead)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if record.ReportHeight != 2 {
+		t.Fatalf("latest record is at %d, expected 2", record.ReportHeight)
+	}
+}
+
+func TestStateBatchKeysWithPrefix(t *testing.T) {
+	state := NewState()
+	state.Set("a/1", []byte("1"))
+	state.Set("a/2", []byte("2"))
+	state.Set("b/1", []byte("1"))
+	batch := state.NewBatch()
+	batch.Delete("a/1")
+	batch.Set("a/3", []byte("3"))
+	batch.Set("b/2", []byte("2"))
+	if keys := batch.KeysWithPrefix("a/"); !reflect.DeepEqual(keys, []string{"a/2", "a/3"}) {
+		t.Fatalf("got keys %v", keys)
+	}
+	if keys := state.KeysWithPrefix("a/"); !reflect.DeepEqual(keys, []string{"a/1", "a/2"}) {
+		t.Fatalf("the batch changed the state's keys to %v", keys)
+	}
+}
+
+// testEthBlockVariant returns a different block at the slot of testEthBlock, as after a reorg or an equivocation.
+func testEthBlockVariant(slot uint64, parentSlot uint64) EthBlockData {
+	data := testEthBlock(slot, parentSlot)
+	data.BlockRoot = testHash("variant", slot)
+	return data
+}
+
+func TestQuorumReplacesReorgedHeadReport(t *testing.T) {
+	reporters := []ed25519.PrivateKey{testReporter(1), testReporter(2), testReporter(3)}
+	r, err := NewRollup(NewMemoryStore(), testGenesis(2, reporters...), make(chan Block, 100))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	executeTestBlock(t, r, testEthTx(t, reporters[0], FinalityHead, testEthBlock(1, 0)))
+	// the head was reorged, so the reporter's vote moves to the new block without jailing it
+	block := executeTestBlock(t, r, testEthTx(t, reporters[0], FinalityHead, testEthBlockVariant(1, 0)))
+	if block.Receipts[0].Status != ReceiptAccepted {
+		t.Fatalf("receipt %+v, expected the head report to be accepted", block.Receipts[0])
+	}
+	pending, err := GetPendingReport(r.Snapshot().tipState, EthereumChainID, FinalityHead, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pending.Candidates) != 1 || !strings.Contains(string(pending.Candidates[0].Data), testEthBlockVariant(1, 0).BlockRoot) {
+		t.Fatalf("pending report %+v, expected only the reorged block", pending)
+	}
+
+	// the moved vote counts towards the quorum of the new block
+	executeTestBlock(t, r, testEthTx(t, reporters[1], FinalityHead, testEthBlockVariant(1, 0)))
+	record, err := GetChainRecord(r.Snapshot().tipState, EthereumChainID, FinalityHead)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if record == nil || record.ReportHeight != 1 || len(record.Reporters) != 2 {
+		t.Fatalf("latest record %+v, expected the reorged block with both reporters", record)
+	}
+
+	// a different head report for a canonical height is rejected, not jailed
+	block = executeTestBlock(t, r, testEthTx(t, reporters[0], FinalityHead, testEthBlock(1, 0)))
+	if block.Receipts[0].Status != ReceiptRejected {
+		t.Fatalf("receipt %+v, expected the report to be rejected", block.Receipts[0])
+	}
+	checkNotJailed(t, r, reporters...)
+}
+
+func TestQuorumJailsFinalizedEquivocation(t *testing.T) {
+	reporters := []ed25519.PrivateKey{testReporter(1), testReporter(2), testReporter(3)}
+	r, err := NewRollup(NewMemoryStore(), testGenesis(2, reporters...), make(chan Block, 100))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	executeTestBlock(t, r, testEthTx(t, reporters[0], FinalityFinalized, testEthBlock(32, 0)))
+	block := executeTestBlock(t, r, testEthTx(t, reporters[0], FinalityFinalized, testEthBlockVariant(32, 0)))
+	if block.Receipts[0].Status != ReceiptJailed {
+		t.Fatalf("receipt %+v, expected the reporter to be jailed", block.Receipts[0])
+	}
+	reporter, err := GetReporter(r.Snapshot().tipState, testPublicKey(reporters[0]))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reporter.Jailed {
+		t.Fatal("reporter was not jailed")
+	}
+	evidence, err := GetEvidence(r.Snapshot().tipState)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(evidence) != 1 || evidence[0].Finality != FinalityFinalized || evidence[0].ReportHeight != 32 {
+		t.Fatalf("evidence %+v, expected the finalized equivocation", evidence)
+	}
+	// the earlier vote doesn't count anymore
+	if pending, err := GetPendingReport(r.Snapshot().tipState, EthereumChainID, FinalityFinalized, 32); err != nil || pending != nil {
+		t.Fatalf("pending report %+v (%v), expected none", pending, err)
+	}
+}
+
+func checkNotJailed(t *testing.T, r *Rollup, reporters ...ed25519.PrivateKey) {
+	t.Helper()
+	for _, key := range reporters {
+		reporter, err := GetReporter(r.Snapshot().tipState, testPublicKey(key))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if reporter.Jailed {
+			t.Fatalf("reporter %s was jailed", reporter.PublicKey)
+		}
+	}
+	if evidence, err := GetEvidence(r.Snapshot().tipState); err != nil || len(evidence) != 0 {
+		t.Fatalf("evidence %+v (%v), expected none", evidence, err)
+	}
+}
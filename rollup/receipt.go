@@ -0,0 +1,28 @@
+package rollup
+
+import "blockchain-oracle/merkle"
+
+type ReceiptStatus string
+
+const (
+	ReceiptAccepted ReceiptStatus = "accepted"
+	ReceiptRejected ReceiptStatus = "rejected"
+	// ReceiptJailed is the status of a justified or finalized report which conflicts with an earlier report of its
+	// reporter. The report is rejected, but the evidence is recorded and the reporter is jailed.
+	ReceiptJailed ReceiptStatus = "jailed"
+	// ReceiptDuplicate is the status of a tx with the same signed content as an earlier tx of the block which wasn't
+	// rejected. It is collapsed into the earlier tx without being executed.
+	ReceiptDuplicate ReceiptStatus = "duplicate"
+)
+
+// Receipt is the result of executing a single tx of a block.
+type Receipt struct {
+	TxIndex uint32 `json:"tx_index"`
+	// TxHash is the sha256 hash of the tx as sequenced.
+	TxHash merkle.Hash   `json:"tx_hash"`
+	Status ReceiptStatus `json:"status"`
+	// Reason explains why a tx was rejected.
+	Reason string `json:"reason,omitempty"`
+	// ChangedKeys are the state keys written by an accepted tx.
+	ChangedKeys []string `json:"changed_keys"`
+}
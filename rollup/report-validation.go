@@ -0,0 +1,120 @@
+package rollup
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// ReportValidator checks a report against the latest canonical record of its chain, which is nil if the chain
+// has none yet. It returns whether the report doesn't build on the latest record, i.e. a reorg was detected.
+// Reports are only validated after their report height was checked to be above the latest record's.
+type ReportValidator func(latest *ChainRecord, record *ChainRecord) (reorg bool, err error)
+
+var (
+	reportValidatorsLock sync.RWMutex
+	reportValidators     = map[PayloadType]ReportValidator{
+		PayloadTypeEthBlock: validateEthReport,
+	}
+)
+
+// RegisterReportValidator registers the validator for a payload type, replacing any existing validator.
+// Like payload decoders, every node has to register the same validators.
+func RegisterReportValidator(payloadType PayloadType, validator ReportValidator) {
+	reportValidatorsLock.Lock()
+	defer reportValidatorsLock.Unlock()
+	reportValidators[payloadType] = validator
+}
+
+func GetReportValidator(payloadType PayloadType) (ReportValidator, bool) {
+	reportValidatorsLock.RLock()
+	defer reportValidatorsLock.RUnlock()
+	validator, ok := reportValidators[payloadType]
+	return validator, ok
+}
+
+// validateReport checks that the report height is above the latest record of the chain and runs the validator of
+// the payload type, setting the record's reorg flag.
+func validateReport(latest *ChainRecord, record *ChainRecord) error {
+	if latest != nil && record.ReportHeight <= latest.ReportHeight {
+		return fmt.Errorf("%s height %d is not above the latest height %d", record.ChainID, record.ReportHeight, latest.ReportHeight)
+	}
+	validator, ok := GetReportValidator(record.PayloadType)
+	if !ok {
+		return nil
+	}
+	reorg, err := validator(latest, record)
+	if err != nil {
+		return err
+	}
+	record.Reorg = reorg
+	return nil
+}
+
+// validateEthReport rejects reports with empty or zero hashes. The execution payload hashes are only checked if
+// they are set, since blocks before the merge don't have them and older reporters don't report all of them. A head
+// report whose parent root isn't the block root of the latest record is a reorg, unless the latest record doesn't
+// have a block root. Justified and finalized reports are checkpoints, which don't link to the previous checkpoint
+// by parent root.
+func validateEthReport(latest *ChainRecord, record *ChainRecord) (bool, error) {
+	payload, err := record.DecodeData()
+	if err != nil {
+		return false, err
+	}
+	data, ok := payload.(*EthBlockData)
+	if !ok {
+		return false, fmt.Errorf("unexpected payload %T", payload)
+	}
+	hashes := []struct {
+		name     string
+		value    string
+		optional bool
+	}{
+		{"block_root", data.BlockRoot, false},
+		{"block_hash", data.BlockHash, true},
+		{"state_root", data.StateRoot, false},
+		{"parent_root", data.ParentRoot, false},
+		{"execution_state_root", data.ExecutionStateRoot, true},
+		{"receipts_root", data.ReceiptsRoot, true},
+		{"withdrawals_root", data.WithdrawalsRoot, true},
+	}
+	for _, hash := range hashes {
+		if hash.optional && hash.value == "" {
+			continue
+		}
+		if err := checkHash(hash.value); err != nil {
+			return false, fmt.Errorf("invalid %s: %w", hash.name, err)
+		}
+	}
+
+	if latest == nil || latest.PayloadType != PayloadTypeEthBlock || record.Finality.orHead() != FinalityHead {
+		return false, nil
+	}
+	latestPayload, err := latest.DecodeData()
+	if err != nil {
+		return false, err
+	}
+	latestData, ok := latestPayload.(*EthBlockData)
+	if !ok || latestData.BlockRoot == "" {
+		return false, nil
+	}
+	return !strings.EqualFold(strings.TrimPrefix(data.ParentRoot, "0x"), strings.TrimPrefix(latestData.BlockRoot, "0x")), nil
+}
+
+// checkHash checks that a hash is 32 hex encoded bytes, optionally 0x prefixed, which aren't all zero.
+func checkHash(hash string) error {
+	bs, err := hex.DecodeString(strings.TrimPrefix(hash, "0x"))
+	if err != nil {
+		return err
+	}
+	if len(bs) != 32 {
+		return fmt.Errorf("hash has length %d, expected 32", len(bs))
+	}
+	if bytes.Equal(bs, make([]byte, 32)) {
+		return errors.New("hash is zero")
+	}
+	return nil
+}
@@ -0,0 +1,115 @@
+package rollup
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+// testEthRecord returns the record of an ethereum report of the given finality.
+func testEthRecord(t *testing.T, finality Finality, data EthBlockData) *ChainRecord {
+	t.Helper()
+	encoded, err := json.Marshal(&data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &ChainRecord{
+		ChainID:      EthereumChainID,
+		ReportHeight: data.Slot,
+		PayloadType:  PayloadTypeEthBlock,
+		Finality:     finality,
+		Data:         encoded,
+	}
+}
+
+func TestValidateReportRejectsInvalidReports(t *testing.T) {
+	latest := testEthRecord(t, FinalityHead, testEthBlock(10, 9))
+	withHash := func(set func(data *EthBlockData)) EthBlockData {
+		data := testEthBlock(11, 10)
+		set(&data)
+		return data
+	}
+	for _, c := range []struct {
+		name string
+		data EthBlockData
+		err  string
+	}{
+		{"same slot as the latest record", testEthBlockVariant(10, 9), "not above the latest height"},
+		{"slot below the latest record", testEthBlock(9, 8), "not above the latest height"},
+		{"empty block root", withHash(func(d *EthBlockData) { d.BlockRoot = "" }), "invalid block_root"},
+		{"zero block root", withHash(func(d *EthBlockData) { d.BlockRoot = "0x" + strings.Repeat("00", 32) }), "invalid block_root"},
+		{"empty state root", withHash(func(d *EthBlockData) { d.StateRoot = "" }), "invalid state_root"},
+		{"short parent root", withHash(func(d *EthBlockData) { d.ParentRoot = "0x1234" }), "invalid parent_root"},
+		{"non hex parent root", withHash(func(d *EthBlockData) { d.ParentRoot = "0x" + strings.Repeat("zz", 32) }), "invalid parent_root"},
+		{"zero execution block hash", withHash(func(d *EthBlockData) { d.BlockHash = "0x" + strings.Repeat("00", 32) }), "invalid block_hash"},
+		{"malformed withdrawals root", withHash(func(d *EthBlockData) { d.WithdrawalsRoot = "0x12" }), "invalid withdrawals_root"},
+	} {
+		err := validateReport(latest, testEthRecord(t, FinalityHead, c.data))
+		if err == nil || !strings.Contains(err.Error(), c.err) {
+			t.Errorf("%s: got error %v, expected %q", c.name, err, c.err)
+		}
+	}
+
+	// the execution hashes are optional, since blocks before the merge don't have them
+	if err := validateReport(latest, testEthRecord(t, FinalityHead, testEthBlock(11, 10))); err != nil {
+		t.Errorf("report without execution hashes: %v", err)
+	}
+}
+
+func TestValidateReportDetectsReorgs(t *testing.T) {
+	latest := testEthRecord(t, FinalityHead, testEthBlock(10, 9))
+	for _, c := range []struct {
+		name     string
+		latest   *ChainRecord
+		finality Finality
+		data     EthBlockData
+		reorg    bool
+	}{
+		{"first report of the chain", nil, FinalityHead, testEthBlock(10, 9), false},
+		{"head building on the latest record", latest, FinalityHead, testEthBlock(11, 10), false},
+		{"head after a missed slot", latest, FinalityHead, testEthBlock(12, 10), false},
+		{"head with another root on the same parent", latest, FinalityHead, testEthBlockVariant(11, 10), false},
+		{"head building on a reorged block", latest, FinalityHead, EthBlockData{
+			BlockRoot:  testHash("block", 11),
+			StateRoot:  testHash("state", 11),
+			ParentRoot: testEthBlockVariant(10, 9).BlockRoot,
+			Slot:       11,
+		}, true},
+		{"checkpoints don't link by parent root", testEthRecord(t, FinalityFinalized, testEthBlock(32, 0)), FinalityFinalized, testEthBlock(64, 63), false},
+	} {
+		record := testEthRecord(t, c.finality, c.data)
+		if err := validateReport(c.latest, record); err != nil {
+			t.Errorf("%s: %v", c.name, err)
+			continue
+		}
+		if record.Reorg != c.reorg {
+			t.Errorf("%s: reorg is %v, expected %v", c.name, record.Reorg, c.reorg)
+		}
+	}
+}
+
+func TestRollupAcceptsReorgedHead(t *testing.T) {
+	reporter := testReporter(1)
+	r, err := NewRollup(NewMemoryStore(), testGenesis(1, reporter), make(chan Block, 100))
+	if err != nil {
+		t.Fatal(err)
+	}
+	executeTestBlock(t, r, testEthTx(t, reporter, FinalityHead, testEthBlock(1, 0)))
+	reorged := EthBlockData{
+		BlockRoot:  testHash("block", 2),
+		StateRoot:  testHash("state", 2),
+		ParentRoot: testEthBlockVariant(1, 0).BlockRoot,
+		Slot:       2,
+	}
+	block := executeTestBlock(t, r, testEthTx(t, reporter, FinalityHead, reorged))
+	if block.Receipts[0].Status != ReceiptAccepted {
+		t.Fatalf("receipt %+v, expected the reorged head to be accepted", block.Receipts[0])
+	}
+	record, err := GetChainRecord(r.Snapshot().tipState, EthereumChainID, FinalityHead)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if record.ReportHeight != 2 || !record.Reorg {
+		t.Fatalf("latest record %+v, expected slot 2 flagged as a reorg", record)
+	}
+}
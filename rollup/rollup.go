@@ -1,10 +1,12 @@
 package rollup
 
 import (
+	"blockchain-oracle/merkle"
 	"bytes"
-	"crypto/sha256"
-	"encoding/json"
 	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	astriaPb "buf.build/gen/go/astria/execution-apis/protocolbuffers/go/astria/execution/v1alpha2"
@@ -12,52 +14,56 @@ import (
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
-// we now need to define a transaction and a block
-
-type Transaction struct {
-	FinalizedEthBlockData EthBlockData `json:"ethBlockData"`
+// HashTxs returns the merkle root of the txs.
+func HashTxs(txs [][]byte) [32]byte {
+	return merkle.Root(txs)
 }
 
-func HashTxs(txs []Transaction) ([32]byte, error) {
-	txBytes := [][]byte{}
-	for _, tx := range txs {
-		if bs, err := json.Marshal(tx); err != nil {
-			return [32]byte{}, err
-		} else {
-			txBytes = append(txBytes, bs)
-		}
-	}
-
-	hash := sha256.Sum256(bytes.Join(txBytes, []byte{}))
-
-	return hash, nil
+// TxProof returns an inclusion proof for the tx at the given index against the block's tx root.
+func (b *Block) TxProof(index int) (*merkle.Proof, error) {
+	return merkle.NewProof(b.Txs, index)
 }
 
 type Block struct {
-	ParentHash [32]byte
-	Hash       [32]byte
-	Height     uint32
-	Timestamp  time.Time
+	BlockHeader
+	Hash [32]byte
 	// ideally each tx will have an individual chains finalized data. like tx1 = eth finalized data, tx2 = solana finalized data etc
-	// if a block has multiple txs with the same chain finalized data, we should consider the last tx as the chains finalized data for the sake of this example
-	Txs []Transaction
+	// the txs are kept as sequenced, in the canonical order of OrderTxs, and decoded when executed. executing them
+	// updates the latest record of each chain in the rollup state, see ApplyTxs
+	Txs [][]byte
+	// Receipts has the result of executing each tx.
+	Receipts []Receipt
 }
 
-func NewBlock(parentHash []byte, height uint32, txs []Transaction, timestamp time.Time) Block {
-	txHash, err := HashTxs(txs)
-	if err != nil {
-		panic(err)
-	}
-
-	return Block{
+func NewBlock(parentHash []byte, height uint32, txs [][]byte, receipts []Receipt, stateRoot [32]byte, timestamp time.Time) Block {
+	header := BlockHeader{
+		Version:    BlockVersion,
 		ParentHash: [32]byte(parentHash),
-		Hash:       txHash,
 		Height:     height,
-		Txs:        txs,
 		Timestamp:  timestamp,
+		TxRoot:     HashTxs(txs),
+		StateRoot:  stateRoot,
+	}
+
+	return Block{
+		BlockHeader: header,
+		Hash:        header.Hash(),
+		Txs:         txs,
+		Receipts:    receipts,
 	}
 }
 
+// VerifyHash checks that the block hash commits to the block header and that the tx root commits to the txs.
+func (b *Block) VerifyHash() error {
+	if HashTxs(b.Txs) != b.TxRoot {
+		return errors.New("tx root does not match block txs")
+	}
+	if b.BlockHeader.Hash() != b.Hash {
+		return errors.New("block hash does not match block header")
+	}
+	return nil
+}
+
 func (b *Block) ToPb() (*astriaPb.Block, error) {
 	return &astriaPb.Block{
 		Number:          b.Height,
@@ -67,82 +73,304 @@ func (b *Block) ToPb() (*astriaPb.Block, error) {
 	}, nil
 }
 
-// GenesisBlock creates the genesis block.
-func GenesisBlock() Block {
-	genesisTx := Transaction{
-		EthBlockData{
-			BlockHash:     "",
-			StateRoot:     "",
-			ParentRoot:    "",
-			Slot:          0,
-			ProposerIndex: 0,
-		},
+// Rollup holds the rollup's blocks, commitment state and state. There is a single writer at a time, the execution
+// server, while readers like the REST API work on immutable snapshots, so they always see a consistent view.
+type Rollup struct {
+	store   BlockStore
+	genesis *Genesis
+	// writeLock serializes writers. Readers never take it.
+	writeLock sync.Mutex
+	snapshot  atomic.Pointer[Snapshot]
+	BlockChan chan Block
+}
+
+// NewRollup loads the rollup from the given store. If the store is empty, it is initialized with the genesis block.
+// The state is loaded from the state persisted with the firm block, and the blocks above it are replayed.
+func NewRollup(store BlockStore, genesis *Genesis, blockChan chan Block) (*Rollup, error) {
+	genesisBlock, err := genesis.Block()
+	if err != nil {
+		return nil, err
 	}
 
-	genesisHash, err := HashTxs([]Transaction{genesisTx})
+	height, err := store.Height()
 	if err != nil {
-		logrus.Errorf("error hashing genesis tx: %s\n", err)
-		panic(err)
+		return nil, err
+	}
+	if height == 0 {
+		logrus.Info("block store is empty, writing genesis block")
+		if err := store.SetRulesVersion(ExecutionRulesVersion); err != nil {
+			return nil, err
+		}
+		if err := store.PutBlock(genesisBlock); err != nil {
+			return nil, err
+		}
+		if err := store.SetCommitment(0, 0); err != nil {
+			return nil, err
+		}
+		height = 1
 	}
 
-	return Block{
-		ParentHash: [32]byte{0x00000000},
-		Hash:       genesisHash,
-		Height:     0,
-		Timestamp:  time.Now(),
-		Txs: []Transaction{
-			genesisTx,
-		},
+	rules, err := store.GetRulesVersion()
+	if err != nil {
+		return nil, err
+	}
+	if rules != ExecutionRulesVersion {
+		return nil, fmt.Errorf("block store was written with execution rules version %d, but this node executes version %d, "+
+			"remove the data dir to resync the rollup from the sequencer", rules, ExecutionRulesVersion)
 	}
-}
 
-type Rollup struct {
-	Blocks    []Block
-	soft      uint32
-	firm      uint32
-	BlockChan chan Block
-}
+	storedGenesis, err := store.GetBlock(0)
+	if err != nil {
+		return nil, err
+	}
+	if storedGenesis.Hash != genesisBlock.Hash {
+		return nil, errors.New("stored genesis block does not match genesis")
+	}
 
-func NewRollup(blockChan chan Block) Rollup {
-	return Rollup{
-		Blocks:    []Block{GenesisBlock()},
-		soft:      0,
-		firm:      0,
-		BlockChan: blockChan,
+	soft, firm, err := store.GetCommitment()
+	if err != nil {
+		return nil, err
+	}
+	if soft >= height {
+		// the node stopped while rolling back, the soft block was removed
+		soft = height - 1
 	}
-}
 
-func (r *Rollup) GetSingleBlock(height uint32) (*Block, error) {
-	if height >= uint32(len(r.Blocks)) {
-		return nil, errors.New("block not found")
+	unfinalized := []Block{}
+	for h := firm; h < height; h++ {
+		block, err := store.GetBlock(h)
+		if err != nil {
+			return nil, err
+		}
+		unfinalized = append(unfinalized, *block)
+	}
+	snapshot := &Snapshot{
+		store:       store,
+		genesis:     genesis,
+		soft:        soft,
+		firm:        firm,
+		unfinalized: unfinalized,
+	}
+
+	state, stateHeight, err := store.LoadState()
+	if err != nil {
+		return nil, err
+	}
+	// stores written before the state was persisted only have blocks, their state is rebuilt from genesis once
+	persistState := state == nil
+	if persistState {
+		state, err = genesis.State()
+		if err != nil {
+			return nil, err
+		}
+		stateHeight = 0
+	}
+	if stateHeight > firm {
+		return nil, fmt.Errorf("persisted state height %d is above firm height %d", stateHeight, firm)
+	}
+	stateBlock, err := snapshot.GetBlock(stateHeight)
+	if err != nil {
+		return nil, err
+	}
+	if state.Root() != stateBlock.StateRoot {
+		return nil, fmt.Errorf("persisted state root does not match state root at height %d", stateHeight)
+	}
+	snapshot.firmState, err = snapshot.replay(state, stateHeight, firm)
+	if err != nil {
+		return nil, err
+	}
+	snapshot.tipState, err = snapshot.replay(snapshot.firmState, firm, height-1)
+	if err != nil {
+		return nil, err
+	}
+	if persistState {
+		changes := map[string][]byte{}
+		snapshot.firmState.Range("", func(key string, value []byte) bool {
+			changes[key] = nonNil(value)
+			return true
+		})
+		if err := store.UpdateState(firm, changes); err != nil {
+			return nil, err
+		}
+	} else if stateHeight < firm {
+		// the node stopped after updating the commitment but before persisting the state
+		changes, err := snapshot.changedState(stateHeight, firm, snapshot.firmState)
+		if err != nil {
+			return nil, err
+		}
+		if err := store.UpdateState(firm, changes); err != nil {
+			return nil, err
+		}
 	}
-	return &r.Blocks[height], nil
-}
 
-func (r *Rollup) GetSoftBlock() *Block {
-	return &r.Blocks[r.soft]
+	logrus.WithFields(logrus.Fields{
+		"height":    height,
+		"soft":      soft,
+		"firm":      firm,
+		"stateRoot": snapshot.tipState.Root(),
+	}).Info("loaded rollup from block store")
+
+	r := &Rollup{
+		store:     store,
+		genesis:   genesis,
+		BlockChan: blockChan,
+	}
+	r.snapshot.Store(snapshot)
+	return r, nil
 }
 
-func (r *Rollup) GetFirmBlock() *Block {
-	return &r.Blocks[r.firm]
+// Snapshot returns the latest snapshot of the rollup.
+func (r *Rollup) Snapshot() *Snapshot {
+	return r.snapshot.Load()
 }
 
-func (r *Rollup) GetLatestBlock() *Block {
-	return &r.Blocks[len(r.Blocks)-1]
+// ExecuteBlock executes the txs on top of the block with the given hash and appends the resulting block.
+// The parent has to be the firm block or a block above it. If it isn't the latest block, e.g. because the conductor
+// re-executes after a restart or a sequencer reorg, the blocks above it are rolled back.
+// The txs are reordered with OrderTxs, so the block doesn't depend on the sequencer's ordering.
+func (r *Rollup) ExecuteBlock(parentHash []byte, txs [][]byte, timestamp time.Time) (*Block, error) {
+	txs = OrderTxs(txs)
+
+	r.writeLock.Lock()
+	defer r.writeLock.Unlock()
+
+	snapshot := r.Snapshot()
+	parent, err := snapshot.GetUnfinalizedBlockByHash(parentHash)
+	if err != nil {
+		return nil, err
+	}
+	block, state, err := snapshot.BuildBlock(parent, txs, timestamp)
+	if err != nil {
+		return nil, err
+	}
+
+	if latest := snapshot.GetLatestBlock(); parent.Height < latest.Height {
+		logrus.WithFields(logrus.Fields{
+			"from": latest.Height,
+			"to":   parent.Height,
+		}).Warn("rolling back to re-execute on an older block")
+		snapshot, err = r.rollback(snapshot, parent.Height)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := r.addBlock(snapshot, block, state); err != nil {
+		return nil, err
+	}
+	return &block, nil
 }
 
-func (r *Rollup) Height() uint32 {
-	return uint32(len(r.Blocks))
+// rollback removes all blocks above the given height and publishes the resulting snapshot. It refuses to roll back
+// past the firm block. The soft block is moved down to the new latest block if needed.
+// It must be called with the write lock held.
+func (r *Rollup) rollback(snapshot *Snapshot, height uint32) (*Snapshot, error) {
+	if height < snapshot.firm {
+		return nil, fmt.Errorf("cannot roll back to height %d below firm height %d", height, snapshot.firm)
+	}
+	state, err := snapshot.GetState(height)
+	if err != nil {
+		return nil, err
+	}
+
+	next := *snapshot
+	if next.soft > height {
+		next.soft = height
+		if err := r.store.SetCommitment(next.soft, next.firm); err != nil {
+			return nil, err
+		}
+	}
+	if err := r.store.DeleteBlocksAbove(height); err != nil {
+		return nil, err
+	}
+	// copy the remaining blocks so that appending to them doesn't overwrite blocks of older snapshots
+	next.unfinalized = append([]Block{}, snapshot.unfinalized[:height-snapshot.firm+1]...)
+	next.tipState = state
+
+	r.snapshot.Store(&next)
+	return &next, nil
 }
 
-func (r *Rollup) AddBlock(block Block) error {
-	if r.GetLatestBlock().Height > 0 && !bytes.Equal(block.ParentHash[:], r.GetLatestBlock().Hash[:]) {
+// addBlock appends the block and the state after it and publishes the resulting snapshot.
+// It must be called with the write lock held.
+func (r *Rollup) addBlock(snapshot *Snapshot, block Block, state *State) error {
+	latest := snapshot.GetLatestBlock()
+	if !bytes.Equal(block.ParentHash[:], latest.Hash[:]) {
 		return errors.New("invalid prev block hash")
 	}
-	r.Blocks = append(r.Blocks, block)
+	if block.Height != latest.Height+1 {
+		return errors.New("invalid block height")
+	}
+	if err := block.VerifyHash(); err != nil {
+		return err
+	}
+	if state.Root() != block.StateRoot {
+		return errors.New("state root does not match block state root")
+	}
+	if err := r.store.PutBlock(block); err != nil {
+		return err
+	}
+
+	next := *snapshot
+	next.unfinalized = append(snapshot.unfinalized, block)
+	next.tipState = state
+	r.snapshot.Store(&next)
+
 	select {
 	case r.BlockChan <- block:
 	default:
 	}
 	return nil
 }
+
+// UpdateCommitment checks the firm block hash, persists the new soft and firm heights and publishes
+// the resulting snapshot.
+func (r *Rollup) UpdateCommitment(soft uint32, firm uint32, firmHash []byte) (*Snapshot, error) {
+	r.writeLock.Lock()
+	defer r.writeLock.Unlock()
+
+	snapshot := r.Snapshot()
+	if firm < snapshot.firm {
+		return nil, fmt.Errorf("firm height %d is below current firm height %d", firm, snapshot.firm)
+	}
+	if soft < firm || soft >= snapshot.Height() {
+		return nil, fmt.Errorf("invalid soft height %d", soft)
+	}
+	firmBlock, err := snapshot.GetBlock(firm)
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(firmBlock.Hash[:], firmHash) {
+		return nil, errors.New("firm block hash mismatch")
+	}
+	firmState, err := snapshot.GetState(firm)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.store.SetCommitment(soft, firm); err != nil {
+		return nil, err
+	}
+	if firm > snapshot.firm {
+		// the state is persisted after the commitment, so that it is never ahead of the firm block
+		changes, err := snapshot.changedState(snapshot.firm, firm, firmState)
+		if err != nil {
+			return nil, err
+		}
+		if err := r.store.UpdateState(firm, changes); err != nil {
+			return nil, err
+		}
+	}
+
+	next := *snapshot
+	next.soft = soft
+	next.firm = firm
+	next.firmState = firmState
+	next.unfinalized = snapshot.unfinalized[firm-snapshot.firm:]
+
+	r.snapshot.Store(&next)
+	return &next, nil
+}
+
+func (r *Rollup) Close() error {
+	return r.store.Close()
+}
@@ -0,0 +1,322 @@
+package rollup
+
+import (
+	"context"
+	"crypto/ed25519"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	astriaPb "buf.build/gen/go/astria/execution-apis/protocolbuffers/go/astria/execution/v1alpha2"
+	"github.com/gorilla/mux"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// TestRollupConcurrentAccess executes blocks, rolls back and advances the commitment through the execution server
+// while the REST API and the execution server's read methods are hammered. It is meant to be run with -race.
+func TestRollupConcurrentAccess(t *testing.T) {
+	for name, newStore := range map[string]func(t *testing.T) BlockStore{
+		"memory": func(t *testing.T) BlockStore { return NewMemoryStore() },
+		"pebble": func(t *testing.T) BlockStore {
+			store, err := NewPebbleStore(t.TempDir())
+			if err != nil {
+				t.Fatal(err)
+			}
+			return store
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			testRollupConcurrentAccess(t, newStore(t))
+		})
+	}
+}
+
+func testRollupConcurrentAccess(t *testing.T, store BlockStore) {
+	reporters := []ed25519.PrivateKey{testReporter(1), testReporter(2), testReporter(3)}
+	genesis := testGenesis(2, reporters...)
+	r, err := NewRollup(store, genesis, make(chan Block, 1000))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	app := &App{
+		restRouter: mux.NewRouter(),
+		rollup:     r,
+		genesis:    genesis,
+		wsClients:  map[*WSClient]bool{},
+	}
+	app.setupRestRoutes()
+	server := app.makeExecutionServer()
+
+	var done atomic.Bool
+	var wg sync.WaitGroup
+	errs := make(chan error, 100)
+
+	// the single writer, like the conductor
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		defer done.Store(true)
+		rng := rand.New(rand.NewSource(1))
+		ctx := context.Background()
+		for slot := uint64(1); slot <= 100; slot++ {
+			snapshot := r.Snapshot()
+			parent := snapshot.GetLatestBlock()
+			// sometimes re-execute on the parent of the latest block, which rolls the latest block back
+			if rng.Intn(5) == 0 && parent.Height > snapshot.Firm() {
+				rolledBack, err := snapshot.GetBlock(parent.Height - 1)
+				if err != nil {
+					errs <- err
+					return
+				}
+				parent = rolledBack
+				slot--
+			}
+			txs := [][]byte{}
+			for _, reporter := range reporters[:1+rng.Intn(len(reporters))] {
+				txs = append(txs, testEthTx(t, reporter, FinalityHead, testEthBlock(slot, slot-1)))
+			}
+			block, err := server.ExecuteBlock(ctx, &astriaPb.ExecuteBlockRequest{
+				PrevBlockHash: parent.Hash[:],
+				Transactions:  txs,
+				Timestamp:     timestamppb.New(parent.Timestamp.Add(time.Second)),
+			})
+			if err != nil {
+				errs <- fmt.Errorf("executing block on %d: %w", parent.Height, err)
+				return
+			}
+			firm := r.Snapshot().GetLatestBlock().Height
+			if rng.Intn(3) != 0 {
+				firm = r.Snapshot().Firm()
+			}
+			firmBlock, err := r.Snapshot().GetBlock(firm)
+			if err != nil {
+				errs <- err
+				return
+			}
+			_, err = server.UpdateCommitmentState(ctx, &astriaPb.UpdateCommitmentStateRequest{
+				CommitmentState: &astriaPb.CommitmentState{
+					Soft: block,
+					Firm: &astriaPb.Block{Number: firm, Hash: firmBlock.Hash[:]},
+				},
+			})
+			if err != nil {
+				errs <- fmt.Errorf("updating commitment: %w", err)
+				return
+			}
+		}
+	}()
+
+	paths := []string{
+		"/block/%d",
+		"/block/%d/receipts",
+		"/block/%d/tx/0/proof",
+		"/chain/ethereum",
+		"/chain/ethereum/pending",
+		"/proof/ethereum",
+		"/governance/audit",
+		"/evidence",
+	}
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			rng := rand.New(rand.NewSource(int64(i)))
+			for !done.Load() {
+				height := rng.Intn(int(r.Snapshot().Height()) + 2)
+				path := paths[rng.Intn(len(paths))]
+				if strings.Contains(path, "%d") {
+					path = fmt.Sprintf(path, height)
+				}
+				rec := httptest.NewRecorder()
+				app.restRouter.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+				if rec.Code != http.StatusOK && rec.Code != http.StatusNotFound {
+					errs <- fmt.Errorf("GET %s returned %d", path, rec.Code)
+					return
+				}
+				if path == "/proof/ethereum" && rec.Code == http.StatusOK {
+					if err := checkProofResponse(r, rec.Body.Bytes()); err != nil {
+						errs <- err
+						return
+					}
+				}
+			}
+		}(i)
+	}
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			rng := rand.New(rand.NewSource(int64(100 + i)))
+			ctx := context.Background()
+			for !done.Load() {
+				if _, err := server.GetCommitmentState(ctx, &astriaPb.GetCommitmentStateRequest{}); err != nil {
+					errs <- err
+					return
+				}
+				snapshot := r.Snapshot()
+				height := uint32(rng.Intn(int(snapshot.Height())))
+				block, err := snapshot.GetBlock(height)
+				if err != nil {
+					errs <- err
+					return
+				}
+				_, err = server.BatchGetBlocks(ctx, &astriaPb.BatchGetBlocksRequest{
+					Identifiers: []*astriaPb.BlockIdentifier{
+						{Identifier: &astriaPb.BlockIdentifier_BlockNumber{BlockNumber: height}},
+						{Identifier: &astriaPb.BlockIdentifier_BlockHash{BlockHash: block.Hash[:]}},
+					},
+				})
+				// a block above the firm block may have been rolled back since the snapshot was taken
+				if err != nil && height <= snapshot.Firm() {
+					errs <- fmt.Errorf("getting firm block %d: %w", height, err)
+					return
+				}
+				state, err := snapshot.GetState(height)
+				if err != nil {
+					errs <- err
+					return
+				}
+				if state.Root() != block.StateRoot {
+					errs <- fmt.Errorf("state root of snapshot at height %d does not match its block", height)
+					return
+				}
+			}
+		}(i)
+	}
+
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Error(err)
+	}
+	if r.Snapshot().Firm() == 0 {
+		t.Error("firm block never advanced")
+	}
+}
+
+// checkProofResponse verifies the proof of a chain record response against the block hash at its height.
+func checkProofResponse(r *Rollup, body []byte) error {
+	res := &ChainRecordProofResponse{}
+	if err := json.Unmarshal(body, res); err != nil {
+		return err
+	}
+	// the proof is of a firm block, which can't be rolled back
+	block, err := r.Snapshot().GetBlock(res.Height)
+	if err != nil {
+		return err
+	}
+	if _, err := VerifyChainRecordProof(block.Hash, EthereumChainID, FinalityHead, res.StateProof); err != nil {
+		return fmt.Errorf("proof at height %d: %w", res.Height, err)
+	}
+	return nil
+}
+
+func TestRollupRollsBackAboveFirmBlock(t *testing.T) {
+	reporter := testReporter(1)
+	genesis := testGenesis(1, reporter)
+	dir := t.TempDir()
+	store, err := NewPebbleStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r, err := NewRollup(store, genesis, make(chan Block, 100))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for slot := uint64(1); slot <= 4; slot++ {
+		executeTestBlock(t, r, testEthTx(t, reporter, FinalityHead, testEthBlock(slot, slot-1)))
+	}
+	firmTestBlock(t, r, 2)
+	checkCommitment := func(height uint32, soft uint32, firm uint32) {
+		t.Helper()
+		snapshot := r.Snapshot()
+		if snapshot.Height() != height || snapshot.Soft() != soft || snapshot.Firm() != firm {
+			t.Fatalf("height %d, soft %d and firm %d, expected %d, %d and %d",
+				snapshot.Height(), snapshot.Soft(), snapshot.Firm(), height, soft, firm)
+		}
+	}
+	checkCommitment(5, 4, 2)
+	blockAt := func(height uint32) *Block {
+		t.Helper()
+		block, err := r.Snapshot().GetBlock(height)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return block
+	}
+
+	// blocks below the firm block are final and can't be built upon
+	parent := blockAt(1)
+	if _, err := r.ExecuteBlock(parent.Hash[:], nil, parent.Timestamp.Add(time.Second)); !errors.Is(err, ErrBlockNotFound) {
+		t.Fatalf("executed on a block below the firm block, got error %v", err)
+	}
+	r.writeLock.Lock()
+	_, err = r.rollback(r.Snapshot(), 1)
+	r.writeLock.Unlock()
+	if err == nil || !strings.Contains(err.Error(), "below firm height") {
+		t.Fatalf("rolled back below the firm block, got error %v", err)
+	}
+	checkCommitment(5, 4, 2)
+	replaced := blockAt(4)
+
+	// re-executing on a soft block rolls the blocks above it back and moves the soft block down
+	parent = blockAt(3)
+	reorged := testEthBlockVariant(4, 3)
+	block, err := r.ExecuteBlock(parent.Hash[:], [][]byte{testEthTx(t, reporter, FinalityHead, reorged)}, parent.Timestamp.Add(time.Second))
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkCommitment(5, 3, 2)
+	if block.Height != 4 || block.Hash == replaced.Hash || blockAt(4).Hash != block.Hash {
+		t.Fatalf("block %d %s, expected a new block 4 replacing %s", block.Height, block.Hash, replaced.Hash)
+	}
+	record, err := GetChainRecord(r.Snapshot().tipState, EthereumChainID, FinalityHead)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if record.ReportHeight != 4 || !strings.Contains(string(record.Data), reorged.BlockRoot) {
+		t.Fatalf("latest record %+v, expected the re-executed report", record)
+	}
+
+	// the firm block itself can be re-executed on
+	parent = blockAt(2)
+	block, err = r.ExecuteBlock(parent.Hash[:], nil, parent.Timestamp.Add(time.Second))
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkCommitment(4, 2, 2)
+	state, err := r.Snapshot().GetState(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if block.StateRoot != state.Root() || r.Snapshot().tipState.Root() != block.StateRoot {
+		t.Fatal("the tip state isn't the state of the firm block after executing an empty block on it")
+	}
+
+	// the rollback is persisted
+	if err := r.Close(); err != nil {
+		t.Fatal(err)
+	}
+	store, err = NewPebbleStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r, err = NewRollup(store, genesis, make(chan Block, 100))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	checkCommitment(4, 2, 2)
+	if latest := r.Snapshot().GetLatestBlock(); latest.Hash != block.Hash {
+		t.Fatalf("latest block %s after reopening, expected %s", latest.Hash, block.Hash)
+	}
+}
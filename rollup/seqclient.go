@@ -3,7 +3,6 @@ package rollup
 import (
 	"context"
 	"crypto/ed25519"
-	"encoding/json"
 	"fmt"
 
 	astriaPb "buf.build/gen/go/astria/astria/protocolbuffers/go/astria/sequencer/v1alpha1"
@@ -13,7 +12,8 @@ import (
 	log "github.com/sirupsen/logrus"
 )
 
-// SequencerClient is a client for interacting with the sequencer.
+// SequencerClient is a client for interacting with the sequencer. It tracks the nonce of the sequencer account, so
+// it isn't safe for concurrent use, and txs must only be sequenced from a single goroutine.
 type SequencerClient struct {
 	c        *client.Client
 	signer   *client.Signer
@@ -25,7 +25,6 @@ type SequencerClient struct {
 func NewSequencerClient(sequencerAddr string, rollupId []byte, private ed25519.PrivateKey) *SequencerClient {
 	log.Debug("creating new sequencer client")
 	signer := client.NewSigner(private)
-	log.Debugf("NewSequencerClient: private key is: %s", private)
 	// default tendermint RPC endpoint
 	c, err := client.NewClient(sequencerAddr)
 	if err != nil {
@@ -48,12 +47,12 @@ func (sc *SequencerClient) broadcastTxSync(tx *astriaPb.SignedTransaction) (*ten
 // SendMessage sends a message as a transaction.
 func (sc *SequencerClient) SequenceTx(tx Transaction) (*tendermintPb.ResultBroadcastTx, error) {
 	log.Debug("sending eth block data!")
-	data, err := json.Marshal(tx)
+	data, err := tx.Bytes()
 	if err != nil {
 		return nil, err
 	}
 	log.Debugf("SequenceTx: signing key is: %s", sc.signer.Address())
-	log.Debugf("SequenceTx: data: %s", data)
+	log.Debugf("SequenceTx: data: %x", data)
 	log.Debugf("SequenceTx: nonce is : %d\n", sc.nonce)
 
 	unsigned := &astriaPb.UnsignedTransaction{
@@ -0,0 +1,168 @@
+package rollup
+
+import (
+	"bytes"
+	"fmt"
+	"time"
+)
+
+// Snapshot is a consistent view of the rollup's blocks, commitment state and state at some point in time.
+// Snapshots are never modified after they are published, and the blocks and states returned from them must
+// not be modified either.
+type Snapshot struct {
+	store   BlockStore
+	genesis *Genesis
+	soft    uint32
+	firm    uint32
+	// unfinalized holds the firm block and all blocks above it, which can still be rolled back.
+	// Blocks below the firm block never change, so they are read from the store.
+	unfinalized []Block
+	// firmState and tipState are the states after the firm and the latest block. The state at any other
+	// height is rebuilt by replaying blocks.
+	firmState *State
+	tipState  *State
+}
+
+// Height returns the number of blocks.
+func (s *Snapshot) Height() uint32 {
+	return s.firm + uint32(len(s.unfinalized))
+}
+
+func (s *Snapshot) Soft() uint32 {
+	return s.soft
+}
+
+func (s *Snapshot) Firm() uint32 {
+	return s.firm
+}
+
+func (s *Snapshot) GetBlock(height uint32) (*Block, error) {
+	if height >= s.Height() {
+		return nil, ErrBlockNotFound
+	}
+	if height >= s.firm {
+		block := s.unfinalized[height-s.firm]
+		return &block, nil
+	}
+	return s.store.GetBlock(height)
+}
+
+func (s *Snapshot) GetSoftBlock() (*Block, error) {
+	return s.GetBlock(s.soft)
+}
+
+func (s *Snapshot) GetFirmBlock() (*Block, error) {
+	return s.GetBlock(s.firm)
+}
+
+func (s *Snapshot) GetLatestBlock() *Block {
+	block := s.unfinalized[len(s.unfinalized)-1]
+	return &block
+}
+
+// GetBlockByHash returns the block with the given hash.
+func (s *Snapshot) GetBlockByHash(hash [32]byte) (*Block, error) {
+	if block, err := s.GetUnfinalizedBlockByHash(hash[:]); err == nil {
+		return block, nil
+	}
+	height, err := s.store.GetHeightByHash(hash)
+	if err != nil {
+		return nil, err
+	}
+	// blocks at or above the firm height in the store may be newer than this snapshot
+	if height >= s.firm {
+		return nil, ErrBlockNotFound
+	}
+	return s.store.GetBlock(height)
+}
+
+// GetUnfinalizedBlockByHash returns the block with the given hash among the firm block and the blocks above it,
+// which are the only blocks that can still be built upon.
+func (s *Snapshot) GetUnfinalizedBlockByHash(hash []byte) (*Block, error) {
+	for i := len(s.unfinalized) - 1; i >= 0; i-- {
+		if bytes.Equal(s.unfinalized[i].Hash[:], hash) {
+			block := s.unfinalized[i]
+			return &block, nil
+		}
+	}
+	return nil, ErrBlockNotFound
+}
+
+// GetState returns the state after the block at the given height.
+func (s *Snapshot) GetState(height uint32) (*State, error) {
+	switch {
+	case height >= s.Height():
+		return nil, ErrBlockNotFound
+	case height == s.Height()-1:
+		return s.tipState, nil
+	case height == s.firm:
+		return s.firmState, nil
+	case height > s.firm:
+		return s.replay(s.firmState, s.firm, height)
+	default:
+		genesisState, err := s.genesis.State()
+		if err != nil {
+			return nil, err
+		}
+		return s.replay(genesisState, 0, height)
+	}
+}
+
+// replay applies the blocks after from up to and including to on top of a copy of the state at from,
+// checking the resulting state roots against the blocks.
+func (s *Snapshot) replay(state *State, from uint32, to uint32) (*State, error) {
+	state = state.Clone()
+	for height := from + 1; height <= to; height++ {
+		block, err := s.GetBlock(height)
+		if err != nil {
+			return nil, err
+		}
+		ApplyTxs(state, height, block.Txs)
+		if state.Root() != block.StateRoot {
+			return nil, fmt.Errorf("state root mismatch at height %d", height)
+		}
+	}
+	return state, nil
+}
+
+// changedState returns the values in the given state after the block at height to of the keys changed by the blocks
+// after from up to and including to. Deleted keys have a nil value, see BlockStore.UpdateState.
+func (s *Snapshot) changedState(from uint32, to uint32, state *State) (map[string][]byte, error) {
+	changes := map[string][]byte{}
+	for height := from + 1; height <= to; height++ {
+		block, err := s.GetBlock(height)
+		if err != nil {
+			return nil, err
+		}
+		for _, receipt := range block.Receipts {
+			for _, key := range receipt.ChangedKeys {
+				if value, ok := state.Get(key); ok {
+					changes[key] = nonNil(value)
+				} else {
+					changes[key] = nil
+				}
+			}
+		}
+	}
+	return changes, nil
+}
+
+// nonNil returns an empty slice for a nil value, since a nil value deletes a key in BlockStore.UpdateState.
+func nonNil(value []byte) []byte {
+	if value == nil {
+		return []byte{}
+	}
+	return value
+}
+
+// BuildBlock executes the txs on top of the parent block and returns the resulting block and state.
+func (s *Snapshot) BuildBlock(parent *Block, txs [][]byte, timestamp time.Time) (Block, *State, error) {
+	parentState, err := s.GetState(parent.Height)
+	if err != nil {
+		return Block{}, nil, err
+	}
+	height := parent.Height + 1
+	state := parentState.Clone()
+	receipts := ApplyTxs(state, height, txs)
+	return NewBlock(parent.Hash[:], height, txs, receipts, state.Root(), timestamp), state, nil
+}
@@ -0,0 +1,47 @@
+package rollup
+
+import (
+	"blockchain-oracle/stateproof"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// StateProof proves that a value is stored under a key in the state after a block, see stateproof.StateProof.
+type StateProof = stateproof.StateProof
+
+// NewStateProof builds a proof for the key in the state after the given block.
+func NewStateProof(block *Block, state *State, key string) (*StateProof, error) {
+	if state.Root() != block.StateRoot {
+		return nil, errors.New("state root does not match block state root")
+	}
+	value, ok := state.Get(key)
+	if !ok {
+		return nil, fmt.Errorf("key %s not found in state", key)
+	}
+	proof, err := state.Proof(key)
+	if err != nil {
+		return nil, err
+	}
+	return &StateProof{
+		Key:       key,
+		Value:     value,
+		BlockHash: block.Hash,
+		Header:    block.BlockHeader,
+		Proof:     proof,
+	}, nil
+}
+
+// VerifyChainRecordProof checks the proof against a trusted block hash and returns the latest record of the finality
+// level of the chain it proves.
+func VerifyChainRecordProof(trustedBlockHash [32]byte, chainID string, finality Finality, p *StateProof) (*ChainRecord, error) {
+	value, err := stateproof.VerifyLatestRecord(trustedBlockHash, chainID, string(finality.orHead()), p)
+	if err != nil {
+		return nil, err
+	}
+	record := &ChainRecord{}
+	if err := json.Unmarshal(value, record); err != nil {
+		return nil, err
+	}
+	return record, nil
+}
@@ -0,0 +1,43 @@
+package rollup
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestChainRecordProof(t *testing.T) {
+	reporter := testReporter(1)
+	r, err := NewRollup(NewMemoryStore(), testGenesis(1, reporter), make(chan Block, 100))
+	if err != nil {
+		t.Fatal(err)
+	}
+	block := executeTestBlock(t, r, testEthTx(t, reporter, FinalityHead, testEthBlock(1, 0)))
+	state, err := r.Snapshot().GetState(block.Height)
+	if err != nil {
+		t.Fatal(err)
+	}
+	proof, err := NewStateProof(block, state, LatestRecordKey(EthereumChainID, FinalityHead))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the proof is served as JSON
+	data, err := json.Marshal(proof)
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded := &StateProof{}
+	if err := json.Unmarshal(data, decoded); err != nil {
+		t.Fatal(err)
+	}
+	record, err := VerifyChainRecordProof(block.Hash, EthereumChainID, "", decoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if record.ReportHeight != 1 {
+		t.Fatalf("proved record has report height %d, expected 1", record.ReportHeight)
+	}
+	if _, err := VerifyChainRecordProof(block.Hash, EthereumChainID, FinalityFinalized, decoded); err == nil {
+		t.Fatal("verified the head proof as a finalized proof")
+	}
+}
@@ -0,0 +1,246 @@
+package rollup
+
+import (
+	"blockchain-oracle/stateproof"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+const EthereumChainID = "ethereum"
+
+// ExecutionRulesVersion is the version of the rules txs are executed with, see ApplyTxs. It has to be bumped with
+// every change which executes existing blocks to a different state, e.g. new validation or tx ordering. Blocks are
+// always replayed with the current rules, so a node refuses to start on a block store written with other rules,
+// see NewRollup, and has to be resynced from the sequencer.
+const ExecutionRulesVersion uint32 = 1
+
+// ChainRecord is the state entry for a chain report accepted by the rollup.
+type ChainRecord struct {
+	ChainID      string `json:"chain_id"`
+	RollupHeight uint32 `json:"rollup_height"`
+	// ReportHeight is the slot or block height of the chain the report is for.
+	ReportHeight uint64      `json:"report_height"`
+	PayloadType  PayloadType `json:"payload_type"`
+	// Finality is the finality level of the reported block, an empty finality is the head.
+	Finality Finality `json:"finality,omitempty"`
+	// Reporters are the hex encoded public keys of the reporters whose matching reports made the record canonical.
+	// It is empty for genesis checkpoints.
+	Reporters []string `json:"reporters,omitempty"`
+	// Reorg is set if the report doesn't build on the previous canonical record of the chain, see ReportValidator.
+	Reorg bool `json:"reorg,omitempty"`
+	// Data is the report payload, which can be decoded with the decoder of the payload type.
+	Data json.RawMessage `json:"data"`
+}
+
+// DecodeData decodes the report payload of the record.
+func (r *ChainRecord) DecodeData() (Payload, error) {
+	tx := Transaction{
+		PayloadType: r.PayloadType,
+		Payload:     r.Data,
+	}
+	return tx.DecodePayload()
+}
+
+// LatestRecordKey is the state key of the latest accepted record of a finality level of a chain.
+func LatestRecordKey(chainID string, finality Finality) string {
+	return stateproof.LatestRecordKey(chainID, string(finality.orHead()))
+}
+
+// HistoryRecordKey is the state key of the accepted record of a finality level of a chain at a slot or block
+// height. The height is zero padded so that history keys sort by height.
+func HistoryRecordKey(chainID string, finality Finality, reportHeight uint64) string {
+	return fmt.Sprintf("%shistory/%020d", chainKeyPrefix(chainID, finality), reportHeight)
+}
+
+// Reporter is a member of the reporter set.
+type Reporter struct {
+	// PublicKey is the hex encoded ed25519 public key of the reporter.
+	PublicKey string `json:"public_key"`
+	// Jailed reporters were caught equivocating. Their reports are rejected until governance unjails them.
+	Jailed bool `json:"jailed,omitempty"`
+}
+
+// ReporterKey is the state key of a reporter.
+func ReporterKey(publicKey string) string {
+	return fmt.Sprintf("reporter/%s", publicKey)
+}
+
+// GetReporter returns the reporter with the given hex encoded public key, or nil if it isn't in the reporter set.
+func GetReporter(state StateReadWriter, publicKey string) (*Reporter, error) {
+	value, ok := state.Get(ReporterKey(publicKey))
+	if !ok {
+		return nil, nil
+	}
+	reporter := &Reporter{}
+	if err := json.Unmarshal(value, reporter); err != nil {
+		return nil, err
+	}
+	return reporter, nil
+}
+
+func setReporter(state StateReadWriter, reporter Reporter) error {
+	value, err := json.Marshal(reporter)
+	if err != nil {
+		return err
+	}
+	state.Set(ReporterKey(reporter.PublicKey), value)
+	return nil
+}
+
+// setChainRecord stores the record as the latest record of its chain and finality level and in their history.
+func setChainRecord(state StateReadWriter, record ChainRecord) error {
+	value, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	state.Set(LatestRecordKey(record.ChainID, record.Finality), value)
+	state.Set(HistoryRecordKey(record.ChainID, record.Finality, record.ReportHeight), value)
+	return nil
+}
+
+// ApplyTxs executes the txs of the block at the given height on the state and returns a receipt per tx.
+// Txs which fail to decode or execute are rejected without changing the state, so that a malformed tx
+// can't halt the chain. Txs with the same sign bytes as an earlier executed tx of the block are collapsed into it.
+// Only txs which were executed count, since a copy of a tx with an invalid signature has the same sign bytes and
+// would otherwise censor the tx it was copied from.
+func ApplyTxs(state *State, height uint32, txs [][]byte) []Receipt {
+	receipts := make([]Receipt, 0, len(txs))
+	executed := map[[32]byte]int{}
+	for i, raw := range txs {
+		receipt := Receipt{
+			TxIndex:     uint32(i),
+			TxHash:      sha256.Sum256(raw),
+			Status:      ReceiptAccepted,
+			ChangedKeys: []string{},
+		}
+		batch := state.NewBatch()
+		var key [32]byte
+		hasKey := false
+		tx, err := DecodeTransaction(raw)
+		if err == nil {
+			key, hasKey = signBytesKey(tx)
+			if first, ok := executed[key]; hasKey && ok {
+				receipt.Status = ReceiptDuplicate
+				receipt.Reason = fmt.Sprintf("duplicate of tx %d", first)
+				receipts = append(receipts, receipt)
+				continue
+			}
+			err = applyTx(batch, height, tx)
+		}
+		var equivocation *EquivocationError
+		switch {
+		case errors.As(err, &equivocation):
+			receipt.Status = ReceiptJailed
+			receipt.Reason = err.Error()
+			receipt.ChangedKeys = batch.ChangedKeys()
+			batch.Commit()
+		case err != nil:
+			receipt.Status = ReceiptRejected
+			receipt.Reason = err.Error()
+		default:
+			receipt.ChangedKeys = batch.ChangedKeys()
+			batch.Commit()
+		}
+		if hasKey && receipt.Status != ReceiptRejected {
+			executed[key] = i
+		}
+		receipts = append(receipts, receipt)
+	}
+	return receipts
+}
+
+// signBytesKey returns the hash of the tx's sign bytes, which txs are collapsed by, see ApplyTxs.
+func signBytesKey(tx *Transaction) ([32]byte, bool) {
+	signBytes, err := tx.SignBytes()
+	if err != nil {
+		// the tx fails to execute anyway
+		return [32]byte{}, false
+	}
+	return sha256.Sum256(signBytes), true
+}
+
+// applyTx submits the tx's report, which becomes the latest record of its chain and finality level once it
+// reaches quorum, see submitReport. Since reports have to be above the latest record, the highest report of a
+// chain which reaches quorum in a block wins, regardless of the order of the txs, see OrderTxs.
+// Only reports signed by a member of the reporter set for enabled chains are accepted. Governance txs are
+// executed by applyGovernanceTx.
+func applyTx(state StateReadWriter, height uint32, tx *Transaction) error {
+	if tx.PayloadType == PayloadTypeGovernance {
+		return applyGovernanceTx(state, height, tx)
+	}
+	if tx.ChainID == GovernanceChainID {
+		return fmt.Errorf("chain id %s is reserved for governance", GovernanceChainID)
+	}
+
+	publicKey, err := tx.VerifySignature()
+	if err != nil {
+		return err
+	}
+	reporterKey := hex.EncodeToString(publicKey)
+	reporter, err := GetReporter(state, reporterKey)
+	if err != nil {
+		return err
+	}
+	if reporter == nil {
+		return fmt.Errorf("reporter %s is not in the reporter set", reporterKey)
+	}
+	if reporter.Jailed {
+		return fmt.Errorf("reporter %s is jailed", reporterKey)
+	}
+	config, err := GetChainConfig(state, tx.ChainID)
+	if err != nil {
+		return err
+	}
+	if config.Disabled {
+		return fmt.Errorf("chain %s is disabled", tx.ChainID)
+	}
+
+	payload, err := tx.DecodePayload()
+	if err != nil {
+		return err
+	}
+	// store the payload re-encoded, so that records don't depend on how the reporter formatted it
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	record := ChainRecord{
+		ChainID:      tx.ChainID,
+		RollupHeight: height,
+		ReportHeight: payload.ReportHeight(),
+		PayloadType:  tx.PayloadType,
+		Finality:     tx.Finality.orHead(),
+		Data:         data,
+	}
+	return submitReport(state, record, reporterKey, tx)
+}
+
+// GetHistoryRecord returns the canonical record of a finality level of a chain at a slot or block height, or nil
+// if there is none.
+func GetHistoryRecord(state StateReadWriter, chainID string, finality Finality, reportHeight uint64) (*ChainRecord, error) {
+	value, ok := state.Get(HistoryRecordKey(chainID, finality, reportHeight))
+	if !ok {
+		return nil, nil
+	}
+	record := &ChainRecord{}
+	if err := json.Unmarshal(value, record); err != nil {
+		return nil, err
+	}
+	return record, nil
+}
+
+// GetChainRecord returns the latest accepted record of a finality level of a chain, or nil if there is none.
+func GetChainRecord(state StateReadWriter, chainID string, finality Finality) (*ChainRecord, error) {
+	value, ok := state.Get(LatestRecordKey(chainID, finality))
+	if !ok {
+		return nil, nil
+	}
+	record := &ChainRecord{}
+	if err := json.Unmarshal(value, record); err != nil {
+		return nil, err
+	}
+	return record, nil
+}
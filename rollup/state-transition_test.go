@@ -0,0 +1,113 @@
+package rollup
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"testing"
+)
+
+func TestApplyTxsIgnoresForgedCopies(t *testing.T) {
+	reporter := testReporter(1)
+	r, err := NewRollup(NewMemoryStore(), testGenesis(1, reporter), make(chan Block, 100))
+	if err != nil {
+		t.Fatal(err)
+	}
+	genuine := testEthTx(t, reporter, FinalityHead, testEthBlock(1, 0))
+
+	// a copy with a garbage signature has the same sign bytes, find one which is ordered before the genuine tx
+	var forged []byte
+	for i := byte(0); forged == nil; i++ {
+		tx, err := DecodeTransaction(genuine)
+		if err != nil {
+			t.Fatal(err)
+		}
+		tx.Signature = hex.EncodeToString(bytes.Repeat([]byte{i}, 64))
+		raw, err := tx.Bytes()
+		if err != nil {
+			t.Fatal(err)
+		}
+		forgedHash, genuineHash := sha256.Sum256(raw), sha256.Sum256(genuine)
+		if bytes.Compare(forgedHash[:], genuineHash[:]) < 0 {
+			forged = raw
+		}
+	}
+	if ordered := OrderTxs([][]byte{genuine, forged}); !bytes.Equal(ordered[0], forged) {
+		t.Fatal("the forged copy isn't ordered first")
+	}
+
+	block := executeTestBlock(t, r, genuine, forged, genuine)
+	statuses := []ReceiptStatus{}
+	for _, receipt := range block.Receipts {
+		statuses = append(statuses, receipt.Status)
+	}
+	// the forged copy is rejected, the genuine tx is executed and its exact copy collapsed into it
+	if len(statuses) != 3 || statuses[0] != ReceiptRejected || statuses[1] != ReceiptAccepted || statuses[2] != ReceiptDuplicate {
+		t.Fatalf("receipt statuses %v, expected rejected, accepted and duplicate", statuses)
+	}
+	if block.Receipts[2].Reason != "duplicate of tx 1" {
+		t.Fatalf("duplicate reason %q, expected the genuine tx", block.Receipts[2].Reason)
+	}
+	record, err := GetChainRecord(r.Snapshot().tipState, EthereumChainID, FinalityHead)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if record == nil || record.ReportHeight != 1 {
+		t.Fatalf("latest record %+v, expected the genuine report", record)
+	}
+}
+
+func TestApplyTxRejectsUnauthorizedReports(t *testing.T) {
+	reporter := testReporter(1)
+	r, err := NewRollup(NewMemoryStore(), testGenesis(1, reporter), make(chan Block, 100))
+	if err != nil {
+		t.Fatal(err)
+	}
+	unsigned, err := NewEthTransaction(testEthBlock(1, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	unsignedRaw, err := unsigned.Bytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	badSignature, err := DecodeTransaction(testEthTx(t, reporter, FinalityHead, testEthBlock(1, 0)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// sign other content, so the signature is well formed but doesn't match the tx
+	other, err := DecodeTransaction(testEthTx(t, reporter, FinalityHead, testEthBlock(2, 1)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	badSignature.Signature = other.Signature
+	badSignatureRaw, err := badSignature.Bytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, c := range []struct {
+		name   string
+		tx     []byte
+		reason string
+	}{
+		{"unsigned", unsignedRaw, "not signed"},
+		{"bad signature", badSignatureRaw, "invalid signature"},
+		{"reporter outside the allowlist", testEthTx(t, testReporter(2), FinalityHead, testEthBlock(1, 0)), "is not in the reporter set"},
+	} {
+		state := r.Snapshot().tipState.Clone()
+		root := state.Root()
+		receipts := ApplyTxs(state, 1, [][]byte{c.tx})
+		if len(receipts) != 1 || receipts[0].Status != ReceiptRejected {
+			t.Errorf("%s: receipts %+v, expected the tx to be rejected", c.name, receipts)
+			continue
+		}
+		if !strings.Contains(receipts[0].Reason, c.reason) {
+			t.Errorf("%s: reason %q, expected %q", c.name, receipts[0].Reason, c.reason)
+		}
+		if len(receipts[0].ChangedKeys) != 0 || state.Root() != root {
+			t.Errorf("%s: the rejected tx changed the state", c.name)
+		}
+	}
+}
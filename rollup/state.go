@@ -0,0 +1,198 @@
+package rollup
+
+import (
+	"blockchain-oracle/merkle"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/google/btree"
+)
+
+// StateReadWriter is implemented by State and StateBatch.
+type StateReadWriter interface {
+	Get(key string) ([]byte, bool)
+	Set(key string, value []byte)
+	Delete(key string)
+	// KeysWithPrefix returns the keys with the given prefix in sorted order.
+	KeysWithPrefix(prefix string) []string
+}
+
+// stateBTreeDegree is the degree of the btree holding the state entries.
+const stateBTreeDegree = 32
+
+type stateEntry struct {
+	key   string
+	value []byte
+}
+
+func lessStateEntry(a stateEntry, b stateEntry) bool {
+	return a.key < b.key
+}
+
+// State is the rollup's key/value oracle state. The state root is the root of a sparse merkle tree of all key/value
+// pairs, see merkle.SparseTree, which is updated incrementally with each write.
+//
+// The entries are kept in a copy-on-write btree and the tree is immutable, so cloning a state is cheap and a clone
+// can be modified while the original is read. Values must not be modified after they are set, since cloned states
+// share them.
+type State struct {
+	entries *btree.BTreeG[stateEntry]
+	tree    *merkle.SparseTree
+	// cloneLock serializes clones, which update the copy-on-write context of the entries. Reads don't take it.
+	cloneLock sync.Mutex
+}
+
+func NewState() *State {
+	return &State{
+		entries: btree.NewG(stateBTreeDegree, lessStateEntry),
+		tree:    &merkle.SparseTree{},
+	}
+}
+
+// newStateFromEntries returns the state with the given entries, whose keys have to be distinct.
+func newStateFromEntries(entries []merkle.SparseEntry) *State {
+	state := NewState()
+	for _, entry := range entries {
+		state.entries.ReplaceOrInsert(stateEntry{key: string(entry.Key), value: entry.Value})
+	}
+	state.tree = merkle.NewSparseTree(entries)
+	return state
+}
+
+func (s *State) Get(key string) ([]byte, bool) {
+	entry, ok := s.entries.Get(stateEntry{key: key})
+	return entry.value, ok
+}
+
+func (s *State) Set(key string, value []byte) {
+	s.entries.ReplaceOrInsert(stateEntry{key: key, value: value})
+	s.tree = s.tree.Set([]byte(key), value)
+}
+
+func (s *State) Delete(key string) {
+	if _, ok := s.entries.Delete(stateEntry{key: key}); ok {
+		s.tree = s.tree.Delete([]byte(key))
+	}
+}
+
+// Clone returns a copy of the state which can be modified without affecting the original.
+func (s *State) Clone() *State {
+	s.cloneLock.Lock()
+	defer s.cloneLock.Unlock()
+	return &State{
+		entries: s.entries.Clone(),
+		tree:    s.tree,
+	}
+}
+
+// Len returns the number of keys in the state.
+func (s *State) Len() int {
+	return s.entries.Len()
+}
+
+// Range calls fn for the key/value pairs whose keys have the given prefix in sorted key order, until fn returns false.
+func (s *State) Range(prefix string, fn func(key string, value []byte) bool) {
+	s.entries.AscendGreaterOrEqual(stateEntry{key: prefix}, func(entry stateEntry) bool {
+		if !strings.HasPrefix(entry.key, prefix) {
+			return false
+		}
+		return fn(entry.key, entry.value)
+	})
+}
+
+// Keys returns all keys in the state in sorted order.
+func (s *State) Keys() []string {
+	return s.KeysWithPrefix("")
+}
+
+// KeysWithPrefix returns the keys with the given prefix in sorted order.
+func (s *State) KeysWithPrefix(prefix string) []string {
+	keys := []string{}
+	s.Range(prefix, func(key string, _ []byte) bool {
+		keys = append(keys, key)
+		return true
+	})
+	return keys
+}
+
+// Root returns the state root.
+func (s *State) Root() merkle.Hash {
+	return s.tree.Root()
+}
+
+// Proof returns an inclusion proof for the key/value pair of the given key against the state root.
+func (s *State) Proof(key string) (*merkle.SparseProof, error) {
+	return s.tree.Prove([]byte(key))
+}
+
+// StateBatch buffers writes on top of a state, so that the writes of a tx can be discarded if the tx is rejected.
+type StateBatch struct {
+	state  *State
+	writes map[string]*[]byte
+}
+
+// NewBatch returns an empty batch on top of the state.
+func (s *State) NewBatch() *StateBatch {
+	return &StateBatch{
+		state:  s,
+		writes: map[string]*[]byte{},
+	}
+}
+
+func (b *StateBatch) Get(key string) ([]byte, bool) {
+	if value, ok := b.writes[key]; ok {
+		if value == nil {
+			return nil, false
+		}
+		return *value, true
+	}
+	return b.state.Get(key)
+}
+
+func (b *StateBatch) Set(key string, value []byte) {
+	b.writes[key] = &value
+}
+
+func (b *StateBatch) Delete(key string) {
+	b.writes[key] = nil
+}
+
+// KeysWithPrefix returns the keys with the given prefix in the state with the writes applied, in sorted order.
+func (b *StateBatch) KeysWithPrefix(prefix string) []string {
+	keys := []string{}
+	for _, key := range b.state.KeysWithPrefix(prefix) {
+		if _, ok := b.writes[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	for key, value := range b.writes {
+		if value != nil && strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// ChangedKeys returns the keys written by the batch in sorted order.
+func (b *StateBatch) ChangedKeys() []string {
+	keys := make([]string, 0, len(b.writes))
+	for k := range b.writes {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Commit applies the writes to the underlying state.
+func (b *StateBatch) Commit() {
+	for k, v := range b.writes {
+		if v == nil {
+			b.state.Delete(k)
+		} else {
+			b.state.Set(k, *v)
+		}
+	}
+	b.writes = map[string]*[]byte{}
+}
@@ -0,0 +1,163 @@
+package rollup
+
+import (
+	"blockchain-oracle/merkle"
+	"errors"
+	"sync"
+)
+
+var ErrBlockNotFound = errors.New("block not found")
+
+// BlockStore persists the rollup's blocks and commitment state.
+// Blocks are stored contiguously by height, starting from the genesis block at height 0.
+type BlockStore interface {
+	// Height returns the number of blocks in the store.
+	Height() (uint32, error)
+	GetBlock(height uint32) (*Block, error)
+	// GetHeightByHash returns the height of the block with the given hash.
+	GetHeightByHash(hash [32]byte) (uint32, error)
+	// PutBlock stores a block at the next height.
+	PutBlock(block Block) error
+	// DeleteBlocksAbove removes all blocks above the given height.
+	DeleteBlocksAbove(height uint32) error
+	// GetCommitment returns the soft and firm heights.
+	GetCommitment() (uint32, uint32, error)
+	SetCommitment(soft uint32, firm uint32) error
+	// GetRulesVersion returns the execution rules version the blocks were executed with, or 0 if it wasn't set.
+	GetRulesVersion() (uint32, error)
+	SetRulesVersion(version uint32) error
+	// LoadState returns the state persisted with UpdateState and the height of the block it is the state after,
+	// or a nil state if none was persisted.
+	LoadState() (*State, uint32, error)
+	// UpdateState persists the state after the block at the given height by applying the changes to the persisted
+	// state. A nil value deletes the key.
+	UpdateState(height uint32, changes map[string][]byte) error
+	Close() error
+}
+
+// MemoryStore is a BlockStore which keeps everything in memory. Its contents are lost on restart.
+type MemoryStore struct {
+	blocks      []Block
+	heights     map[[32]byte]uint32
+	soft        uint32
+	firm        uint32
+	state       map[string][]byte
+	stateHeight uint32
+	rules       uint32
+	lock        sync.RWMutex
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		blocks:  []Block{},
+		heights: map[[32]byte]uint32{},
+	}
+}
+
+func (m *MemoryStore) Height() (uint32, error) {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+	return uint32(len(m.blocks)), nil
+}
+
+func (m *MemoryStore) GetBlock(height uint32) (*Block, error) {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+	if height >= uint32(len(m.blocks)) {
+		return nil, ErrBlockNotFound
+	}
+	block := m.blocks[height]
+	return &block, nil
+}
+
+func (m *MemoryStore) GetHeightByHash(hash [32]byte) (uint32, error) {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+	height, ok := m.heights[hash]
+	if !ok {
+		return 0, ErrBlockNotFound
+	}
+	return height, nil
+}
+
+func (m *MemoryStore) PutBlock(block Block) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	if block.Height != uint32(len(m.blocks)) {
+		return errors.New("block height does not match store height")
+	}
+	m.blocks = append(m.blocks, block)
+	m.heights[block.Hash] = block.Height
+	return nil
+}
+
+func (m *MemoryStore) DeleteBlocksAbove(height uint32) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	for height+1 < uint32(len(m.blocks)) {
+		delete(m.heights, m.blocks[len(m.blocks)-1].Hash)
+		m.blocks = m.blocks[:len(m.blocks)-1]
+	}
+	return nil
+}
+
+func (m *MemoryStore) GetCommitment() (uint32, uint32, error) {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+	return m.soft, m.firm, nil
+}
+
+func (m *MemoryStore) SetCommitment(soft uint32, firm uint32) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.soft = soft
+	m.firm = firm
+	return nil
+}
+
+func (m *MemoryStore) GetRulesVersion() (uint32, error) {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+	return m.rules, nil
+}
+
+func (m *MemoryStore) SetRulesVersion(version uint32) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.rules = version
+	return nil
+}
+
+func (m *MemoryStore) LoadState() (*State, uint32, error) {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+	if m.state == nil {
+		return nil, 0, nil
+	}
+	entries := make([]merkle.SparseEntry, 0, len(m.state))
+	for key, value := range m.state {
+		entries = append(entries, merkle.SparseEntry{Key: []byte(key), Value: value})
+	}
+	return newStateFromEntries(entries), m.stateHeight, nil
+}
+
+func (m *MemoryStore) UpdateState(height uint32, changes map[string][]byte) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	if m.state == nil {
+		m.state = map[string][]byte{}
+	}
+	for key, value := range changes {
+		if value == nil {
+			delete(m.state, key)
+		} else {
+			m.state[key] = value
+		}
+	}
+	m.stateHeight = height
+	return nil
+}
+
+func (m *MemoryStore) Close() error {
+	return nil
+}
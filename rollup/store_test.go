@@ -0,0 +1,105 @@
+package rollup
+
+import (
+	"testing"
+)
+
+func TestRollupRestoresPersistedState(t *testing.T) {
+	reporter := testReporter(1)
+	genesis := testGenesis(1, reporter)
+	dir := t.TempDir()
+
+	store, err := NewPebbleStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r, err := NewRollup(store, genesis, make(chan Block, 100))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for slot := uint64(1); slot <= 10; slot++ {
+		executeTestBlock(t, r, testEthTx(t, reporter, FinalityHead, testEthBlock(slot, slot-1)))
+	}
+	firmTestBlock(t, r, 4)
+	firmTestBlock(t, r, 7)
+	tipRoot := r.Snapshot().tipState.Root()
+	if err := r.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	store, err = NewPebbleStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+	state, height, err := store.LoadState()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if height != 7 {
+		t.Fatalf("persisted state height is %d, expected 7", height)
+	}
+	firmBlock, err := store.GetBlock(7)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if state.Root() != firmBlock.StateRoot {
+		t.Fatal("persisted state does not match the firm block's state root")
+	}
+
+	r, err = NewRollup(store, genesis, make(chan Block, 100))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Snapshot().tipState.Root() != tipRoot {
+		t.Fatal("tip state root changed after restart")
+	}
+}
+
+func TestRollupRefusesStoreOfOtherRulesVersion(t *testing.T) {
+	genesis := testGenesis(1, testReporter(1))
+	dir := t.TempDir()
+
+	store, err := NewPebbleStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r, err := NewRollup(store, genesis, make(chan Block, 100))
+	if err != nil {
+		t.Fatal(err)
+	}
+	executeTestBlock(t, r, testEthTx(t, testReporter(1), FinalityHead, testEthBlock(1, 0)))
+	if err := r.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, c := range []struct {
+		name    string
+		version uint32
+		refused bool
+	}{
+		{"current rules", ExecutionRulesVersion, false},
+		{"store from before the rules version", 0, true},
+		{"newer rules", ExecutionRulesVersion + 1, true},
+	} {
+		store, err := NewPebbleStore(dir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := store.SetRulesVersion(c.version); err != nil {
+			t.Fatal(err)
+		}
+		r, err := NewRollup(store, genesis, make(chan Block, 100))
+		if (err != nil) != c.refused {
+			t.Fatalf("%s: got error %v, expected refused to be %v", c.name, err, c.refused)
+		}
+		if r != nil {
+			err = r.Close()
+		} else {
+			err = store.Close()
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+}
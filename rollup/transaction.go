@@ -0,0 +1,366 @@
+package rollup
+
+import (
+	"crypto/ed25519"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// TxFormatVersion is the current version of the tx envelope.
+const TxFormatVersion uint32 = 1
+
+// txSignDomain prefixes the bytes reporters sign, so that their signatures can't be used for other messages.
+const txSignDomain = "blockchain-oracle/tx/v1:"
+
+// Transaction is the envelope of every tx sequenced by the oracle. The payload is decoded by the decoder
+// registered for its payload type, so that new chains can be added without changing the envelope.
+type Transaction struct {
+	Version     uint32          `json:"version"`
+	ChainID     string          `json:"chain_id"`
+	PayloadType PayloadType     `json:"payload_type"`
+	Payload     json.RawMessage `json:"payload"`
+	// Finality is the finality level of the reported block. It is empty for head reports and governance txs.
+	Finality Finality `json:"finality,omitempty"`
+	// Reporter is the hex encoded ed25519 public key of the reporter which signed the tx.
+	Reporter string `json:"reporter"`
+	// Signature is the hex encoded signature of the reporter over SignBytes.
+	Signature string `json:"signature"`
+	// Cosignatures are signatures of further signers over SignBytes, for governance txs which need the approval
+	// of multiple admins.
+	Cosignatures []TxSignature `json:"cosignatures,omitempty"`
+}
+
+// TxSignature is a signature over the SignBytes of a tx.
+type TxSignature struct {
+	// PublicKey is the hex encoded ed25519 public key of the signer.
+	PublicKey string `json:"public_key"`
+	Signature string `json:"signature"`
+}
+
+// legacyTransaction is the tx format used before the envelope, which only supported ethereum.
+type legacyTransaction struct {
+	FinalizedEthBlockData *EthBlockData `json:"ethBlockData"`
+}
+
+// NewTransaction wraps the payload in an envelope of the current version.
+func NewTransaction(chainID string, payloadType PayloadType, payload Payload) (Transaction, error) {
+	payloadBytes, err := json.Marshal(payload)
+	if err != nil {
+		return Transaction{}, err
+	}
+	return Transaction{
+		Version:     TxFormatVersion,
+		ChainID:     chainID,
+		PayloadType: payloadType,
+		Payload:     payloadBytes,
+	}, nil
+}
+
+// NewEthTransaction wraps ethereum block data in a tx.
+func NewEthTransaction(data EthBlockData) (Transaction, error) {
+	return NewTransaction(EthereumChainID, PayloadTypeEthBlock, &data)
+}
+
+// Bytes returns the encoding of the transaction which is sequenced: the protobuf format byte followed by
+// the Transaction message.
+func (tx *Transaction) Bytes() ([]byte, error) {
+	msg, err := tx.marshalProto(true)
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte{EncodingProto}, msg...), nil
+}
+
+// SignBytes returns the canonical encoding of the tx which the reporter and cosigners sign: the Transaction message
+// without the signatures. It doesn't depend on how the tx was encoded when it was sequenced.
+func (tx *Transaction) SignBytes() ([]byte, error) {
+	msg, err := tx.marshalProto(false)
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(txSignDomain), msg...), nil
+}
+
+// marshalProto encodes the tx as a Transaction message. Payloads are protobuf encoded if their type supports it
+// and JSON encoded otherwise.
+func (tx *Transaction) marshalProto(withSignature bool) ([]byte, error) {
+	reporter, err := hex.DecodeString(tx.Reporter)
+	if err != nil {
+		return nil, fmt.Errorf("invalid reporter: %w", err)
+	}
+	signature, err := hex.DecodeString(tx.Signature)
+	if err != nil {
+		return nil, fmt.Errorf("invalid signature: %w", err)
+	}
+
+	b := appendVarintField(nil, 1, uint64(tx.Version))
+	b = appendStringField(b, 2, tx.ChainID)
+	b = appendStringField(b, 3, string(tx.PayloadType))
+
+	protoPayload, err := tx.marshalProtoPayload()
+	if err != nil {
+		return nil, err
+	}
+	if protoPayload != nil {
+		b = appendMessageField(b, 4, protoPayload)
+	} else {
+		b = appendMessageField(b, 5, tx.Payload)
+	}
+
+	b = appendBytesField(b, 6, reporter)
+	if withSignature {
+		b = appendBytesField(b, 7, signature)
+		for _, cosignature := range tx.Cosignatures {
+			publicKey, err := hex.DecodeString(cosignature.PublicKey)
+			if err != nil {
+				return nil, fmt.Errorf("invalid cosigner: %w", err)
+			}
+			signature, err := hex.DecodeString(cosignature.Signature)
+			if err != nil {
+				return nil, fmt.Errorf("invalid cosignature: %w", err)
+			}
+			msg := appendBytesField(nil, 1, publicKey)
+			msg = appendBytesField(msg, 2, signature)
+			b = appendMessageField(b, 8, msg)
+		}
+	}
+	b = appendStringField(b, 9, string(tx.Finality))
+	return b, nil
+}
+
+// marshalProtoPayload returns the protobuf encoding of the payload, or nil if its type doesn't have one.
+func (tx *Transaction) marshalProtoPayload() ([]byte, error) {
+	if _, ok := GetProtoPayloadDecoder(tx.PayloadType); !ok {
+		return nil, nil
+	}
+	payload, err := tx.DecodePayload()
+	if err != nil {
+		return nil, err
+	}
+	protoPayload, ok := payload.(ProtoPayload)
+	if !ok {
+		return nil, nil
+	}
+	return protoPayload.MarshalProto()
+}
+
+// Sign sets the reporter of the tx to the public key of the given private key and signs the tx.
+func (tx *Transaction) Sign(private ed25519.PrivateKey) error {
+	tx.Reporter = hex.EncodeToString(private.Public().(ed25519.PublicKey))
+	tx.Signature = ""
+	signBytes, err := tx.SignBytes()
+	if err != nil {
+		return err
+	}
+	tx.Signature = hex.EncodeToString(ed25519.Sign(private, signBytes))
+	return nil
+}
+
+// Cosign adds a signature of the given private key to the tx. The tx has to be signed by its reporter first.
+func (tx *Transaction) Cosign(private ed25519.PrivateKey) error {
+	signBytes, err := tx.SignBytes()
+	if err != nil {
+		return err
+	}
+	tx.Cosignatures = append(tx.Cosignatures, TxSignature{
+		PublicKey: hex.EncodeToString(private.Public().(ed25519.PublicKey)),
+		Signature: hex.EncodeToString(ed25519.Sign(private, signBytes)),
+	})
+	return nil
+}
+
+// VerifySignature checks the signature of the tx and returns the public key of its reporter.
+func (tx *Transaction) VerifySignature() (ed25519.PublicKey, error) {
+	if tx.Reporter == "" || tx.Signature == "" {
+		return nil, errors.New("transaction is not signed")
+	}
+	signBytes, err := tx.SignBytes()
+	if err != nil {
+		return nil, err
+	}
+	return verifySignature(signBytes, TxSignature{PublicKey: tx.Reporter, Signature: tx.Signature})
+}
+
+// VerifyCosignatures checks the cosignatures of the tx and returns the public keys of the cosigners.
+func (tx *Transaction) VerifyCosignatures() ([]ed25519.PublicKey, error) {
+	signBytes, err := tx.SignBytes()
+	if err != nil {
+		return nil, err
+	}
+	cosigners := make([]ed25519.PublicKey, 0, len(tx.Cosignatures))
+	for _, cosignature := range tx.Cosignatures {
+		publicKey, err := verifySignature(signBytes, cosignature)
+		if err != nil {
+			return nil, fmt.Errorf("cosigner %s: %w", cosignature.PublicKey, err)
+		}
+		cosigners = append(cosigners, publicKey)
+	}
+	return cosigners, nil
+}
+
+func verifySignature(signBytes []byte, sig TxSignature) (ed25519.PublicKey, error) {
+	publicKey, err := decodePublicKey(sig.PublicKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid public key: %w", err)
+	}
+	signature, err := hex.DecodeString(sig.Signature)
+	if err != nil {
+		return nil, fmt.Errorf("invalid signature: %w", err)
+	}
+	if !ed25519.Verify(publicKey, signBytes, signature) {
+		return nil, errors.New("invalid signature")
+	}
+	return publicKey, nil
+}
+
+// DecodePayload decodes the payload with the decoder registered for the payload type.
+func (tx *Transaction) DecodePayload() (Payload, error) {
+	decoder, ok := GetPayloadDecoder(tx.PayloadType)
+	if !ok {
+		return nil, fmt.Errorf("unknown payload type %s", tx.PayloadType)
+	}
+	payload, err := decoder(tx.Payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode %s payload: %w", tx.PayloadType, err)
+	}
+	return payload, nil
+}
+
+// DecodeTransaction decodes a tx as sequenced. Txs without the protobuf format byte are decoded as JSON,
+// and txs in the legacy ethereum only format are converted to an envelope, so that old blocks can still be executed.
+func DecodeTransaction(raw []byte) (*Transaction, error) {
+	if len(raw) > 0 && raw[0] == EncodingProto {
+		tx, err := decodeProtoTransaction(raw[1:])
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode transaction: %w", err)
+		}
+		return tx, tx.validate()
+	}
+
+	fields := map[string]json.RawMessage{}
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal transaction: %w", err)
+	}
+
+	if _, ok := fields["version"]; !ok {
+		legacy := &legacyTransaction{}
+		if err := json.Unmarshal(raw, legacy); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal legacy transaction: %w", err)
+		}
+		if legacy.FinalizedEthBlockData == nil {
+			return nil, errors.New("transaction has neither a version nor legacy ethereum block data")
+		}
+		payload, err := json.Marshal(legacy.FinalizedEthBlockData)
+		if err != nil {
+			return nil, err
+		}
+		return &Transaction{
+			Version:     0,
+			ChainID:     EthereumChainID,
+			PayloadType: PayloadTypeEthBlock,
+			Payload:     payload,
+		}, nil
+	}
+
+	tx := &Transaction{}
+	if err := json.Unmarshal(raw, tx); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal transaction: %w", err)
+	}
+	return tx, tx.validate()
+}
+
+func (tx *Transaction) validate() error {
+	if tx.Version != TxFormatVersion {
+		return fmt.Errorf("unsupported transaction version %d", tx.Version)
+	}
+	if tx.ChainID == "" {
+		return errors.New("transaction is missing a chain id")
+	}
+	if err := validateChainID(tx.ChainID); err != nil {
+		return err
+	}
+	return tx.Finality.validate()
+}
+
+// decodeProtoTransaction decodes a Transaction message. A protobuf payload is converted to JSON, so that the
+// rest of the rollup doesn't depend on how a tx was sequenced.
+func decodeProtoTransaction(msg []byte) (*Transaction, error) {
+	tx := &Transaction{}
+	var protoPayload []byte
+	hasProtoPayload := false
+	err := consumeProtoFields(msg, func(field protoField) error {
+		var err error
+		switch field.Num {
+		case 1:
+			tx.Version, err = field.uint32()
+		case 2:
+			tx.ChainID, err = field.string()
+		case 3:
+			var payloadType string
+			payloadType, err = field.string()
+			tx.PayloadType = PayloadType(payloadType)
+		case 4:
+			protoPayload, err = field.bytesValue()
+			hasProtoPayload = true
+		case 5:
+			var payload []byte
+			payload, err = field.bytesValue()
+			tx.Payload = append(json.RawMessage{}, payload...)
+			hasProtoPayload = false
+		case 6:
+			var reporter []byte
+			reporter, err = field.bytesValue()
+			tx.Reporter = hex.EncodeToString(reporter)
+		case 7:
+			var signature []byte
+			signature, err = field.bytesValue()
+			tx.Signature = hex.EncodeToString(signature)
+		case 8:
+			var msg []byte
+			if msg, err = field.bytesValue(); err != nil {
+				return err
+			}
+			cosignature := TxSignature{}
+			err = consumeProtoFields(msg, func(field protoField) error {
+				value, err := field.bytesValue()
+				switch field.Num {
+				case 1:
+					cosignature.PublicKey = hex.EncodeToString(value)
+				case 2:
+					cosignature.Signature = hex.EncodeToString(value)
+				default:
+					return nil
+				}
+				return err
+			})
+			tx.Cosignatures = append(tx.Cosignatures, cosignature)
+		case 9:
+			var finality string
+			finality, err = field.string()
+			tx.Finality = Finality(finality)
+		}
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !hasProtoPayload {
+		return tx, nil
+	}
+
+	decoder, ok := GetProtoPayloadDecoder(tx.PayloadType)
+	if !ok {
+		return nil, fmt.Errorf("no protobuf decoder for payload type %s", tx.PayloadType)
+	}
+	payload, err := decoder(protoPayload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode %s payload: %w", tx.PayloadType, err)
+	}
+	tx.Payload, err = json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+	return tx, nil
+}
@@ -0,0 +1,46 @@
+package rollup
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestDecodeTransactionConvertsLegacyTxs(t *testing.T) {
+	data := testEthBlock(7, 6)
+	raw := fmt.Sprintf(`{"ethBlockData":{"block_root":%q,"state_root":%q,"parent_root":%q,"slot":7,"proposer_index":3}}`,
+		data.BlockRoot, data.StateRoot, data.ParentRoot)
+	tx, err := DecodeTransaction([]byte(raw))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tx.Version != 0 || tx.ChainID != EthereumChainID || tx.PayloadType != PayloadTypeEthBlock {
+		t.Fatalf("legacy tx decoded into %+v, expected an ethereum block envelope", tx)
+	}
+	payload, err := tx.DecodePayload()
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded, ok := payload.(*EthBlockData)
+	if !ok {
+		t.Fatalf("payload %T, expected ethereum block data", payload)
+	}
+	data.ProposerIndex = 3
+	if *decoded != data {
+		t.Fatalf("payload %+v, expected %+v", *decoded, data)
+	}
+
+	for _, c := range []struct {
+		name string
+		raw  string
+		err  string
+	}{
+		{"without block data", `{"foo":1}`, "neither a version nor legacy ethereum block data"},
+		{"malformed block data", `{"ethBlockData":"foo"}`, "failed to unmarshal legacy transaction"},
+	} {
+		_, err := DecodeTransaction([]byte(c.raw))
+		if err == nil || !strings.Contains(err.Error(), c.err) {
+			t.Errorf("%s: got error %v, expected %q", c.name, err, c.err)
+		}
+	}
+}
@@ -0,0 +1,42 @@
+package stateproof
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"time"
+)
+
+// BlockVersion is the version of the block header format.
+const BlockVersion uint32 = 1
+
+// headerEncodedLen is the length of an encoded BlockHeader: version (4) | parent hash (32) | height (4) |
+// timestamp seconds (8) | timestamp nanos (4) | tx root (32) | state root (32)
+const headerEncodedLen = 4 + 32 + 4 + 8 + 4 + 32 + 32
+
+// BlockHeader contains all the fields a block hash commits to.
+type BlockHeader struct {
+	Version    uint32
+	ParentHash [32]byte
+	Height     uint32
+	Timestamp  time.Time
+	TxRoot     [32]byte
+	StateRoot  [32]byte
+}
+
+// Encode returns the canonical binary encoding of the header. All integers are big endian.
+func (h *BlockHeader) Encode() []byte {
+	buf := make([]byte, 0, headerEncodedLen)
+	buf = binary.BigEndian.AppendUint32(buf, h.Version)
+	buf = append(buf, h.ParentHash[:]...)
+	buf = binary.BigEndian.AppendUint32(buf, h.Height)
+	buf = binary.BigEndian.AppendUint64(buf, uint64(h.Timestamp.Unix()))
+	buf = binary.BigEndian.AppendUint32(buf, uint32(h.Timestamp.Nanosecond()))
+	buf = append(buf, h.TxRoot[:]...)
+	buf = append(buf, h.StateRoot[:]...)
+	return buf
+}
+
+// Hash returns the sha256 hash of the encoded header.
+func (h *BlockHeader) Hash() [32]byte {
+	return sha256.Sum256(h.Encode())
+}
@@ -0,0 +1,60 @@
+// Package stateproof verifies proofs of the oracle rollup's state. It only depends on the merkle package, so that
+// light clients can check the records served by a node against a trusted block hash without importing the rollup.
+package stateproof
+
+import (
+	"blockchain-oracle/merkle"
+	"errors"
+	"fmt"
+)
+
+// StateProof proves that Value is stored under Key in the state after the block with hash BlockHash.
+// The header links the block hash to the state root which the merkle proof is checked against.
+type StateProof struct {
+	Key       string              `json:"key"`
+	Value     []byte              `json:"value"`
+	BlockHash merkle.Hash         `json:"block_hash"`
+	Header    BlockHeader         `json:"header"`
+	Proof     *merkle.SparseProof `json:"proof"`
+}
+
+// Verify checks the proof against a block hash obtained from a trusted source, e.g. the firm block
+// posted to the sequencer.
+func (p *StateProof) Verify(trustedBlockHash [32]byte) error {
+	if p.BlockHash != trustedBlockHash {
+		return errors.New("proof is for a different block")
+	}
+	if p.Header.Hash() != trustedBlockHash {
+		return errors.New("header does not match block hash")
+	}
+	if p.Proof == nil {
+		return errors.New("missing merkle proof")
+	}
+	return p.Proof.VerifyKV(p.Header.StateRoot, []byte(p.Key), p.Value)
+}
+
+// ChainKeyPrefix is the prefix of the state keys of a finality level of a chain. Head keys, with an empty or "head"
+// finality, are directly under the chain.
+func ChainKeyPrefix(chainID string, finality string) string {
+	if finality == "" || finality == "head" {
+		return fmt.Sprintf("chain/%s/", chainID)
+	}
+	return fmt.Sprintf("chain/%s/%s/", chainID, finality)
+}
+
+// LatestRecordKey is the state key of the latest record of a finality level of a chain.
+func LatestRecordKey(chainID string, finality string) string {
+	return ChainKeyPrefix(chainID, finality) + "latest"
+}
+
+// VerifyLatestRecord checks the proof against a trusted block hash and returns the JSON encoded latest record of the
+// finality level of the chain it proves.
+func VerifyLatestRecord(trustedBlockHash [32]byte, chainID string, finality string, p *StateProof) ([]byte, error) {
+	if p.Key != LatestRecordKey(chainID, finality) {
+		return nil, fmt.Errorf("proof is not for the latest %s record of chain %s", finality, chainID)
+	}
+	if err := p.Verify(trustedBlockHash); err != nil {
+		return nil, err
+	}
+	return p.Value, nil
+}
@@ -0,0 +1,40 @@
+package stateproof
+
+import (
+	"blockchain-oracle/merkle"
+	"testing"
+	"time"
+)
+
+func TestVerifyLatestRecord(t *testing.T) {
+	key := LatestRecordKey("ethereum", "finalized")
+	value := []byte(`{"chain_id":"ethereum"}`)
+	tree := merkle.NewSparseTree([]merkle.SparseEntry{
+		{Key: []byte(key), Value: value},
+		{Key: []byte(LatestRecordKey("ethereum", "head")), Value: []byte(`{}`)},
+	})
+	proof, err := tree.Prove([]byte(key))
+	if err != nil {
+		t.Fatal(err)
+	}
+	header := BlockHeader{Version: BlockVersion, Height: 7, Timestamp: time.Unix(1700000000, 5).UTC(), StateRoot: tree.Root()}
+	p := &StateProof{Key: key, Value: value, BlockHash: header.Hash(), Header: header, Proof: proof}
+
+	got, err := VerifyLatestRecord(header.Hash(), "ethereum", "finalized", p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != string(value) {
+		t.Fatalf("got %s, expected %s", got, value)
+	}
+	if _, err := VerifyLatestRecord(header.Hash(), "ethereum", "head", p); err == nil {
+		t.Fatal("verified the proof for a different finality")
+	}
+	if _, err := VerifyLatestRecord([32]byte{1}, "ethereum", "finalized", p); err == nil {
+		t.Fatal("verified the proof against a different block hash")
+	}
+	p.Value = []byte(`{"chain_id":"other"}`)
+	if _, err := VerifyLatestRecord(header.Hash(), "ethereum", "finalized", p); err == nil {
+		t.Fatal("verified a modified value")
+	}
+}